@@ -2,65 +2,29 @@
 package actions
 
 import (
-	"fmt"
 	"log"
-	"os"
 	"os/exec"
 	"sync"
 	"time"
 
 	"github.com/Bahaaio/pomo/config"
-	"github.com/gen2brain/beeep"
 )
 
-// RunPostActions sends task notification and runs post commands using goroutines
-//
-// returns a wait group to wait for their completion
-func RunPostActions(task *config.Task) *sync.WaitGroup {
+// RunPostActions sends the task notification through notifiers and runs the post commands at the same time,
+// it waits for both and returns the failures of the notifiers.
+func RunPostActions(task *config.Task, notifiers []config.Notifier, terminal config.Terminal) error {
 	var wg sync.WaitGroup
-	wg.Add(2)
-
-	go func() {
-		defer wg.Done()
-		sendNotification(task.Notification)
-	}()
+	wg.Add(1)
 
 	go func() {
 		defer wg.Done()
 		runPostCommands(task.Then)
 	}()
 
-	return &wg
-}
-
-// sends a notification using the beeep package
-func sendNotification(notification config.Notification) {
-	if !notification.Enabled {
-		log.Println("notification disabled")
-		return
-	}
-
-	log.Println("sending notification")
+	err := Notify(notifiers, terminal, task.Notification)
+	wg.Wait()
 
-	// use the embedded icon
-	var icon any = config.Icon
-
-	// if the user has specified an icon
-	// use that instead
-	if len(notification.Icon) > 0 {
-		icon = notification.Icon
-	}
-
-	var err error
-	if notification.Urgent {
-		err = beeep.Alert(notification.Title, notification.Message, icon)
-	} else {
-		err = beeep.Notify(notification.Title, notification.Message, icon)
-	}
-
-	if err != nil {
-		log.Println("failed to send notification:", err)
-	}
+	return err
 }
 
 // runs the post commands specified in the task
@@ -68,10 +32,16 @@ func runPostCommands(cmds [][]string) {
 	log.Println("running post commands")
 
 	for _, cmd := range cmds {
+		// invalid configs may contain empty commands when not strict
+		if len(cmd) == 0 || cmd[0] == "" {
+			log.Println("skipping empty command")
+			continue
+		}
+
 		c := exec.Command(cmd[0], cmd[1:]...)
 
 		if err := c.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "failed to run command '%q': %v\n", cmd, err)
+			log.Printf("failed to run command %q: %v", cmd, err)
 		}
 
 		// wait some time before running the next command
@@ -0,0 +1,285 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bahaaio/pomo/config"
+	"github.com/gen2brain/beeep"
+)
+
+// how long remote notifiers may take to deliver a notification
+const notifyTimeout = 10 * time.Second
+
+// Notifier sends notifications through a backend.
+type Notifier interface {
+	Notify(notification config.Notification) error
+}
+
+// Factory creates the backend configured by notifier,
+// terminal is the terminal config of the effective config.
+type Factory func(notifier config.Notifier, terminal config.Terminal) (Notifier, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{
+		config.NotifierDesktop: func(config.Notifier, config.Terminal) (Notifier, error) {
+			return Desktop{}, nil
+		},
+		config.NotifierTerminal: func(_ config.Notifier, terminal config.Terminal) (Notifier, error) {
+			return Terminal{W: TerminalOutput, Config: terminal}, nil
+		},
+		config.NotifierBell: func(config.Notifier, config.Terminal) (Notifier, error) {
+			return Bell{W: TerminalOutput}, nil
+		},
+		config.NotifierSocket: func(notifier config.Notifier, _ config.Terminal) (Notifier, error) {
+			return Socket{Path: notifier.Path}, nil
+		},
+		config.NotifierNtfy: func(notifier config.Notifier, _ config.Terminal) (Notifier, error) {
+			return Ntfy{URL: notifier.URL, Token: notifier.Token}, nil
+		},
+		config.NotifierWebhook: func(notifier config.Notifier, _ config.Terminal) (Notifier, error) {
+			return Webhook{URL: notifier.URL, Headers: notifier.Headers}, nil
+		},
+		config.NotifierCommand: func(notifier config.Notifier, _ config.Terminal) (Notifier, error) {
+			if len(notifier.Command) == 0 || notifier.Command[0] == "" {
+				return nil, errors.New("command notifier without a command")
+			}
+			return Command{Args: notifier.Command}, nil
+		},
+		config.NotifierFile: func(notifier config.Notifier, _ config.Terminal) (Notifier, error) {
+			return File{Path: notifier.Path}, nil
+		},
+	}
+)
+
+// Register makes a notifier type available, replacing the factory of a registered type.
+// Configs using the type pass validation from then on.
+func Register(notifierType string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[notifierType] = factory
+	config.RegisterNotifierType(notifierType)
+}
+
+// Unregister removes a notifier type added with [Register].
+func Unregister(notifierType string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry, notifierType)
+	config.UnregisterNotifierType(notifierType)
+}
+
+// NewNotifier returns the backend configured by notifier.
+// Only terminal notifiers get disabled notifications, their bell rings regardless.
+func NewNotifier(notifier config.Notifier, terminal config.Terminal) (Notifier, error) {
+	registryMu.RLock()
+	factory, ok := registry[notifier.Type]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown notifier type %q", notifier.Type)
+	}
+
+	backend, err := factory(notifier, terminal)
+	if err != nil || notifier.Type == config.NotifierTerminal {
+		return backend, err
+	}
+
+	return enabledOnly{backend}, nil
+}
+
+// skips disabled notifications
+type enabledOnly struct {
+	Notifier
+}
+
+func (n enabledOnly) Notify(notification config.Notification) error {
+	if !notification.Enabled {
+		return nil
+	}
+
+	return n.Notifier.Notify(notification)
+}
+
+// NotifierError is the failure of a single notifier.
+type NotifierError struct {
+	// Index is the position of the notifier in its list
+	Index int
+	Type  string
+	Err   error
+}
+
+func (e *NotifierError) Error() string {
+	return fmt.Sprintf("%s notifier: %v", e.Type, e.Err)
+}
+
+func (e *NotifierError) Unwrap() error {
+	return e.Err
+}
+
+// Notify sends a notification through notifiers at the same time,
+// terminal configures the notifiers writing to the terminal.
+// It reads no global config, so it may run while the config is reloaded.
+// The returned error joins a [NotifierError] for every notifier that failed.
+func Notify(notifiers []config.Notifier, terminal config.Terminal, notification config.Notification) error {
+	if !notification.Enabled {
+		log.Println("notification disabled")
+	}
+
+	errs := make([]error, len(notifiers))
+
+	var wg sync.WaitGroup
+	for i, n := range notifiers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			notifier, err := NewNotifier(n, terminal)
+			if err == nil {
+				err = notifier.Notify(notification)
+			}
+
+			if err != nil {
+				log.Printf("failed to notify through %s: %v", n.Type, err)
+				errs[i] = &NotifierError{Index: i, Type: n.Type, Err: err}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Desktop sends notifications to the desktop of the machine pomo runs on.
+type Desktop struct{}
+
+func (Desktop) Notify(notification config.Notification) error {
+	log.Println("sending desktop notification")
+
+	// use the embedded icon
+	var icon any = config.Icon
+
+	// if the user has specified an icon
+	// use that instead
+	if len(notification.Icon) > 0 {
+		icon = notification.Icon
+	}
+
+	if notification.Urgent {
+		return beeep.Alert(notification.Title, notification.Message, icon)
+	}
+
+	return beeep.Notify(notification.Title, notification.Message, icon)
+}
+
+// Terminal rings the bell and sends notification escape sequences to W,
+// terminals show them on the client even over SSH.
+type Terminal struct {
+	W      io.Writer
+	Config config.Terminal
+}
+
+func (t Terminal) Notify(notification config.Notification) error {
+	return NotifyTerminal(t.W, t.Config, notification)
+}
+
+// Bell rings the terminal bell.
+type Bell struct {
+	W io.Writer
+}
+
+func (b Bell) Notify(config.Notification) error {
+	_, err := io.WriteString(b.W, "\a")
+	return err
+}
+
+// Command runs a command for each notification,
+// {title} and {message} in its arguments are replaced.
+type Command struct {
+	Args []string
+
+	// Timeout is how long the command may run, 0 for the default of the other notifiers
+	Timeout time.Duration
+}
+
+func (c Command) Notify(notification config.Notification) error {
+	replacer := strings.NewReplacer("{title}", notification.Title, "{message}", notification.Message)
+
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = replacer.Replace(arg)
+	}
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = notifyTimeout
+	}
+
+	// a hanging command must not keep pomo from quitting
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	// children of the command may keep its output open after it was killed
+	cmd.WaitDelay = time.Second
+	cmd.Env = append(
+		os.Environ(),
+		"POMO_TITLE="+notification.Title,
+		"POMO_MESSAGE="+notification.Message,
+		"POMO_URGENT="+strconv.FormatBool(notification.Urgent),
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%q: %w", c.Args, ctx.Err())
+		}
+
+		if len(output) > 0 {
+			return fmt.Errorf("%q: %w: %s", c.Args, err, strings.TrimSpace(string(output)))
+		}
+
+		return fmt.Errorf("%q: %w", c.Args, err)
+	}
+
+	return nil
+}
+
+// File appends notifications to a log file, one line each.
+type File struct {
+	Path string
+}
+
+func (f File) Notify(notification config.Notification) error {
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	line := time.Now().Format(time.RFC3339) + "\t" + notification.Title + "\t" + notification.Message
+	if notification.Urgent {
+		line += "\turgent"
+	}
+
+	// keep one notification per line
+	line = strings.NewReplacer("\r", " ", "\n", " ").Replace(line)
+
+	if _, err := fmt.Fprintln(file, line); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,216 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+// records the notifications it is sent, for testing
+type mockNotifier struct {
+	notifications chan config.Notification
+	err           error
+}
+
+func newMockNotifier(err error) *mockNotifier {
+	return &mockNotifier{notifications: make(chan config.Notification, 10), err: err}
+}
+
+// registers a notifier type for the duration of the test
+func register(t *testing.T, notifierType string, factory Factory) {
+	t.Helper()
+
+	Register(notifierType, factory)
+	t.Cleanup(func() { Unregister(notifierType) })
+}
+
+func (m *mockNotifier) Notify(notification config.Notification) error {
+	m.notifications <- notification
+	return m.err
+}
+
+func TestNewNotifier(t *testing.T) {
+	testCases := []struct {
+		name     string
+		notifier config.Notifier
+		wantErr  bool
+	}{
+		{"desktop", config.Notifier{Type: config.NotifierDesktop}, false},
+		{"terminal", config.Notifier{Type: config.NotifierTerminal}, false},
+		{"socket", config.Notifier{Type: config.NotifierSocket, Path: "/tmp/pomo.sock"}, false},
+		{"ntfy", config.Notifier{Type: config.NotifierNtfy, URL: "https://ntfy.example.com/pomo"}, false},
+		{"command", config.Notifier{Type: config.NotifierCommand, Command: []string{"true"}}, false},
+		{"bell", config.Notifier{Type: config.NotifierBell}, false},
+		{"webhook", config.Notifier{Type: config.NotifierWebhook, URL: "https://example.com/hooks/pomo"}, false},
+		{"file", config.Notifier{Type: config.NotifierFile, Path: "/tmp/pomo.log"}, false},
+		{"command without command", config.Notifier{Type: config.NotifierCommand}, true},
+		{"unknown type", config.Notifier{Type: "pager"}, true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewNotifier(tt.notifier, config.Terminal{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewNotifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNotify_ReportsFailedNotifiers(t *testing.T) {
+	working, failing := newMockNotifier(nil), newMockNotifier(errors.New("offline"))
+	register(t, "working", func(config.Notifier, config.Terminal) (Notifier, error) { return working, nil })
+	register(t, "failing", func(config.Notifier, config.Terminal) (Notifier, error) { return failing, nil })
+
+	notifiers := []config.Notifier{{Type: "working"}, {Type: "failing"}, {Type: "pager"}}
+	err := Notify(notifiers, config.Terminal{}, config.Notification{Enabled: true, Title: "work finished"})
+
+	// every notifier is tried
+	if len(working.notifications) != 1 || len(failing.notifications) != 1 {
+		t.Fatalf("sent %d and %d notifications, want 1 each", len(working.notifications), len(failing.notifications))
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Notify() error = %v, want joined notifier errors", err)
+	}
+
+	var failed []string
+	for _, err := range joined.Unwrap() {
+		var notifierErr *NotifierError
+		if !errors.As(err, &notifierErr) {
+			t.Fatalf("error %v is not a NotifierError", err)
+		}
+		failed = append(failed, fmt.Sprintf("%d %s", notifierErr.Index, notifierErr.Type))
+	}
+
+	if want := []string{"1 failing", "2 pager"}; !slices.Equal(failed, want) {
+		t.Fatalf("failed notifiers = %v, want %v", failed, want)
+	}
+}
+
+func TestRegister_Validation(t *testing.T) {
+	cfg := config.Config{Notifiers: []config.Notifier{{Type: "pager"}}}
+
+	// reports whether the notifier type is a problem of cfg
+	invalidType := func() bool {
+		for _, problem := range cfg.Validate() {
+			if problem.Key == "notifiers[0].type" {
+				return true
+			}
+		}
+		return false
+	}
+
+	register(t, "pager", func(config.Notifier, config.Terminal) (Notifier, error) { return newMockNotifier(nil), nil })
+	if invalidType() {
+		t.Fatalf("registered notifier type is invalid")
+	}
+
+	Unregister("pager")
+	if !invalidType() {
+		t.Fatalf("unregistered notifier type is valid")
+	}
+}
+
+func TestNotify_DisabledNotification(t *testing.T) {
+	mock := newMockNotifier(nil)
+	register(t, "mock", func(config.Notifier, config.Terminal) (Notifier, error) { return mock, nil })
+
+	if err := Notify([]config.Notifier{{Type: "mock"}}, config.Terminal{}, config.Notification{Title: "disabled"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if len(mock.notifications) != 0 {
+		t.Fatalf("disabled notification was sent")
+	}
+}
+
+func TestEnabledOnly(t *testing.T) {
+	mock := newMockNotifier(errors.New("unreachable"))
+	notifier := enabledOnly{mock}
+
+	if err := notifier.Notify(config.Notification{Title: "disabled"}); err != nil {
+		t.Fatalf("disabled notification returned %v", err)
+	}
+	if len(mock.notifications) != 0 {
+		t.Fatalf("disabled notification was sent")
+	}
+
+	if err := notifier.Notify(config.Notification{Enabled: true, Title: "enabled"}); err == nil {
+		t.Fatalf("error of the backend was not returned")
+	}
+	if got := <-mock.notifications; got.Title != "enabled" {
+		t.Fatalf("sent %q, want %q", got.Title, "enabled")
+	}
+}
+
+func TestFileNotify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	file := File{Path: path}
+
+	for _, notification := range []config.Notification{
+		{Enabled: true, Title: "work finished", Message: "time to\ntake a break"},
+		{Enabled: true, Title: "break over", Message: "back to work!", Urgent: true},
+	} {
+		if err := file.Notify(notification); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log has %d lines, want 2: %q", len(lines), content)
+	}
+
+	for i, want := range []string{"\twork finished\ttime to take a break", "\tbreak over\tback to work!\turgent"} {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Fatalf("line %d = %q, want suffix %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestCommandNotify(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+	command := Command{Args: []string{"sh", "-c", `printf '%s|%s|%s' "$1" "$POMO_MESSAGE" "$POMO_URGENT" > "$2"`, "sh", "{title}!", out}}
+
+	err := command.Notify(config.Notification{Enabled: true, Title: "work finished", Message: "take a break", Urgent: true})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if want := "work finished!|take a break|true"; string(content) != want {
+		t.Fatalf("command wrote %q, want %q", content, want)
+	}
+
+	failing := Command{Args: []string{"sh", "-c", "echo nope; exit 3"}}
+	if err := failing.Notify(config.Notification{Enabled: true}); err == nil {
+		t.Fatalf("failing command returned no error")
+	}
+
+	hanging := Command{Args: []string{"sh", "-c", "sleep 10"}, Timeout: 50 * time.Millisecond}
+	if err := hanging.Notify(config.Notification{Enabled: true}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("hanging command error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,52 @@
+package actions
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+// Ntfy publishes notifications to a topic of an ntfy server,
+// the ntfy apps show them on phones and desktops.
+type Ntfy struct {
+	// URL is the topic URL, e.g. https://ntfy.example.com/pomo
+	URL string
+
+	// Token is sent as a bearer token if set
+	Token string
+
+	Client *http.Client
+}
+
+func (n Ntfy) Notify(notification config.Notification) error {
+	request, err := http.NewRequest(http.MethodPost, n.URL, strings.NewReader(notification.Message))
+	if err != nil {
+		return err
+	}
+
+	// headers are ASCII, ntfy decodes encoded words
+	request.Header.Set("Title", mime.BEncoding.Encode("utf-8", notification.Title))
+
+	if notification.Urgent {
+		request.Header.Set("Priority", "high")
+	}
+
+	if n.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	response, err := httpClient(n.Client).Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("ntfy server responded with %s", response.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package actions
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+func TestNtfyNotify(t *testing.T) {
+	type request struct {
+		path, title, priority, authorization, body string
+	}
+	requests := make(chan request, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		title, _ := new(mime.WordDecoder).DecodeHeader(r.Header.Get("Title"))
+
+		requests <- request{r.URL.Path, title, r.Header.Get("Priority"), r.Header.Get("Authorization"), string(body)}
+	}))
+	defer server.Close()
+
+	ntfy := Ntfy{URL: server.URL + "/pomo", Token: "tk_secret", Client: server.Client()}
+	err := ntfy.Notify(config.Notification{Enabled: true, Urgent: true, Title: "work finished 🎉", Message: "time to take a break!"})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	want := request{"/pomo", "work finished 🎉", "high", "Bearer tk_secret", "time to take a break!"}
+	if got := <-requests; got != want {
+		t.Fatalf("request = %+v, want %+v", got, want)
+	}
+}
+
+func TestNtfyNotifyRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ntfy := Ntfy{URL: server.URL + "/pomo", Client: server.Client()}
+	if err := ntfy.Notify(config.Notification{Enabled: true, Title: "work finished"}); err == nil {
+		t.Fatalf("rejected notification returned no error")
+	}
+}
@@ -0,0 +1,85 @@
+package actions
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"time"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+// Socket sends notifications to a unix socket as JSON lines.
+// Forwarding the socket with ssh -R and reading it with [Serve] on the client
+// shows notifications of a remote pomo on the local desktop.
+type Socket struct {
+	Path string
+}
+
+// socketMessage is a notification as sent over a socket,
+// icons are paths on the remote machine and left out
+type socketMessage struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	Urgent  bool   `json:"urgent,omitempty"`
+}
+
+func (s Socket) Notify(notification config.Notification) error {
+	conn, err := net.DialTimeout("unix", s.Path, notifyTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(notifyTimeout)); err != nil {
+		return err
+	}
+
+	return json.NewEncoder(conn).Encode(socketMessage{
+		Title:   notification.Title,
+		Message: notification.Message,
+		Urgent:  notification.Urgent,
+	})
+}
+
+// Serve reads the notifications sent by [Socket] notifiers to listener
+// and passes them on to notifier, until listener is closed.
+func Serve(listener net.Listener, notifier Notifier) error {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		go serveConn(conn, notifier)
+	}
+}
+
+func serveConn(conn net.Conn, notifier Notifier) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var message socketMessage
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			log.Println("skipping invalid notification:", err)
+			continue
+		}
+
+		notification := config.Notification{
+			Enabled: true,
+			Title:   message.Title,
+			Message: message.Message,
+			Urgent:  message.Urgent,
+		}
+
+		if err := notifier.Notify(notification); err != nil {
+			log.Println("failed to pass on notification:", err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package actions
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+func TestSocketNotify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.sock")
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets are not supported: %v", err)
+	}
+
+	mock := newMockNotifier(nil)
+	done := make(chan error)
+	go func() { done <- Serve(listener, mock) }()
+
+	sent := config.Notification{Enabled: true, Urgent: true, Title: "work finished", Message: "time to take a break!", Icon: "/remote/icon.png"}
+	if err := (Socket{Path: path}).Notify(sent); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	select {
+	case got := <-mock.notifications:
+		// icons are paths on the remote machine
+		sent.Icon = ""
+		if got != sent {
+			t.Fatalf("received %+v, want %+v", got, sent)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("notification was not received")
+	}
+
+	listener.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+}
+
+func TestSocketNotifyWithoutListener(t *testing.T) {
+	socket := Socket{Path: filepath.Join(t.TempDir(), "missing.sock")}
+	if err := socket.Notify(config.Notification{Enabled: true}); err == nil {
+		t.Fatalf("notifying a missing socket returned no error")
+	}
+}
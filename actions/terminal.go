@@ -0,0 +1,88 @@
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+// TerminalOutput is the terminal terminal and bell notifiers write to.
+// Programs drawing on the terminal write through it as well,
+// so notifications never land in the middle of a frame.
+var TerminalOutput = &LockedFile{f: os.Stdout}
+
+// LockedFile is a file whose writes don't interleave.
+type LockedFile struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func (l *LockedFile) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.f.Write(p)
+}
+
+func (l *LockedFile) Read(p []byte) (int, error) {
+	return l.f.Read(p)
+}
+
+func (l *LockedFile) Close() error {
+	return l.f.Close()
+}
+
+// Fd lets terminal libraries detect the terminal behind the file.
+func (l *LockedFile) Fd() uintptr {
+	return l.f.Fd()
+}
+
+// NotifyTerminal writes the bell and the notification escape sequences configured in terminal to w.
+func NotifyTerminal(w io.Writer, terminal config.Terminal, notification config.Notification) error {
+	var sequence strings.Builder
+
+	if notification.Enabled {
+		switch terminal.Notification {
+		case config.TerminalNotificationOSC9:
+			body := notification.Title
+			if notification.Message != "" {
+				body += ": " + notification.Message
+			}
+
+			fmt.Fprintf(&sequence, "\x1b]9;%s\x07", escapeOSC(body))
+
+		case config.TerminalNotificationOSC777:
+			// semicolons separate the title from the message
+			fmt.Fprintf(
+				&sequence, "\x1b]777;notify;%s;%s\x07",
+				strings.ReplaceAll(escapeOSC(notification.Title), ";", ","),
+				escapeOSC(notification.Message),
+			)
+		}
+	}
+
+	if terminal.Bell {
+		sequence.WriteString("\a")
+	}
+
+	if sequence.Len() == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(w, sequence.String())
+	return err
+}
+
+// removes control characters that would end the sequence early
+func escapeOSC(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+}
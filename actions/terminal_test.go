@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+func TestNotifyTerminal(t *testing.T) {
+	notification := config.Notification{Enabled: true, Title: "work finished; nice", Message: "time to\ntake a break"}
+
+	testCases := []struct {
+		name         string
+		terminal     config.Terminal
+		notification config.Notification
+		want         string
+	}{
+		{"nothing configured", config.Terminal{Notification: config.TerminalNotificationNone}, notification, ""},
+		{"bell", config.Terminal{Bell: true}, notification, "\a"},
+		{
+			"osc 9",
+			config.Terminal{Notification: config.TerminalNotificationOSC9},
+			notification,
+			"\x1b]9;work finished; nice: time totake a break\x07",
+		},
+		{
+			"osc 777 with bell",
+			config.Terminal{Bell: true, Notification: config.TerminalNotificationOSC777},
+			notification,
+			"\x1b]777;notify;work finished, nice;time totake a break\x07\a",
+		},
+		{
+			"disabled notification still rings",
+			config.Terminal{Bell: true, Notification: config.TerminalNotificationOSC9},
+			config.Notification{Title: "work finished"},
+			"\a",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+
+			if err := NotifyTerminal(&out, tt.terminal, tt.notification); err != nil {
+				t.Fatalf("NotifyTerminal() error = %v", err)
+			}
+
+			if out.String() != tt.want {
+				t.Fatalf("NotifyTerminal() wrote %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+// Webhook posts notifications as JSON to URL.
+type Webhook struct {
+	URL     string
+	Headers map[string]string
+
+	Client *http.Client
+}
+
+// webhookPayload is the body of webhook requests
+type webhookPayload struct {
+	App     string `json:"app"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	Urgent  bool   `json:"urgent"`
+}
+
+func (w Webhook) Notify(notification config.Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		App:     config.AppName,
+		Title:   notification.Title,
+		Message: notification.Message,
+		Urgent:  notification.Urgent,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range w.Headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := httpClient(w.Client).Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", response.Status)
+	}
+
+	return nil
+}
+
+// returns client, or a client with the notify timeout if nil
+func httpClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+
+	return &http.Client{Timeout: notifyTimeout}
+}
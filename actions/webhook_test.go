@@ -0,0 +1,53 @@
+package actions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+func TestWebhookNotify(t *testing.T) {
+	type request struct {
+		contentType, authorization string
+		payload                    webhookPayload
+	}
+	requests := make(chan request, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		requests <- request{r.Header.Get("Content-Type"), r.Header.Get("Authorization"), payload}
+	}))
+	defer server.Close()
+
+	// viper lowercases the keys of maps
+	webhook := Webhook{URL: server.URL, Headers: map[string]string{"authorization": "Bearer secret"}, Client: server.Client()}
+	err := webhook.Notify(config.Notification{Enabled: true, Title: "work finished", Message: "time to take a break!"})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	want := request{"application/json", "Bearer secret", webhookPayload{App: config.AppName, Title: "work finished", Message: "time to take a break!"}}
+	if got := <-requests; got != want {
+		t.Fatalf("request = %+v, want %+v", got, want)
+	}
+}
+
+func TestWebhookNotifyRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := Webhook{URL: server.URL, Client: server.Client()}
+	if err := webhook.Notify(config.Notification{Enabled: true}); err == nil {
+		t.Fatalf("rejected notification returned no error")
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"os"
 	"time"
 
+	"github.com/Bahaaio/pomo/config"
 	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +21,7 @@ var addCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		duration, err := time.ParseDuration(args[0])
 		if err != nil || duration <= 0 {
-			fmt.Fprintf(os.Stderr, "invalid duration: %q\n", args[0])
+			fmt.Fprintln(os.Stderr, i18n.Tf("invalid duration: %q", args[0]))
 			die(nil)
 		}
 
@@ -29,12 +31,18 @@ var addCmd = &cobra.Command{
 		}
 
 		repo := db.NewSessionRepo(database)
-		err = repo.CreateSessionWithSource(time.Now(), duration, db.WorkSession, db.OtherSource)
+		_, err = repo.InsertSession(db.Session{
+			StartedAt: time.Now(),
+			Duration:  duration,
+			Type:      string(db.WorkSession),
+			Source:    string(db.OtherSource),
+			Profile:   config.C.Profile,
+		})
 		if err != nil {
 			die(err)
 		}
 
-		fmt.Printf("Added %s manual work time to today (source: other).\n", duration)
+		fmt.Println(i18n.Tf("Added %s manual work time to today (source: other).", duration))
 	},
 }
 
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/Bahaaio/pomo/config"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and manage the config file",
+	Example: `  pomo config path       # Print the config file location
+  pomo config show       # Print the effective config
+  pomo config init       # Write an example config file
+  pomo config edit       # Open the config file in $EDITOR
+  pomo config validate   # Check the config file against the schema`,
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path := mustConfigPath()
+		fmt.Println(path)
+
+		if _, err := os.Stat(path); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("config file does not exist, using defaults"))
+		}
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective config after applying profiles and environment overrides",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		out, err := config.Marshal(config.C)
+		if err != nil {
+			die(err)
+		}
+
+		fmt.Print(string(out))
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write an example config file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		path := mustConfigPath()
+
+		if _, err := os.Stat(path); err == nil && !force {
+			die(errors.New(i18n.Tf("config file already exists: %s (use --force to overwrite)", path)))
+		}
+
+		if err := writeExampleConfig(path); err != nil {
+			die(err)
+		}
+
+		fmt.Println(i18n.Tf("wrote config file: %s", path))
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in your editor",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path := mustConfigPath()
+
+		// create the config file first so there is something to edit
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			if err := writeExampleConfig(path); err != nil {
+				die(err)
+			}
+		}
+
+		editor := exec.Command(getEditor(), path)
+		editor.Stdin = os.Stdin
+		editor.Stdout = os.Stdout
+		editor.Stderr = os.Stderr
+
+		if err := editor.Run(); err != nil {
+			die(errors.New(i18n.Tf("failed to run editor: %v", err)))
+		}
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate a config file against the config schema",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := mustConfigPath()
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		problems, err := config.ValidateFile(path)
+		if err != nil {
+			die(err)
+		}
+
+		if len(problems) == 0 {
+			fmt.Println(i18n.Tf("%s: config is valid", path))
+			return
+		}
+
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, problem)
+		}
+		die(errors.New(i18n.Tf("found %d problem(s) in %s", len(problems), path)))
+	},
+}
+
+func init() {
+	configInitCmd.Flags().BoolP("force", "f", false, "overwrite an existing config file")
+
+	configCmd.AddCommand(configPathCmd, configShowCmd, configInitCmd, configEditCmd, configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func mustConfigPath() string {
+	path, err := config.FilePath()
+	if err != nil {
+		die(err)
+	}
+
+	return path
+}
+
+func writeExampleConfig(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, config.Example, 0o644)
+}
+
+// returns the user's preferred editor
+func getEditor() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(env); editor != "" {
+			return editor
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+
+	return "vi"
+}
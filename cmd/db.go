@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the session database",
+	Example: `  pomo db status    # Show the schema version and pending migrations
+  pomo db migrate   # Apply pending migrations`,
+}
+
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the database location and migration status",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := showDBStatus(); err != nil {
+			die(err)
+		}
+	},
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending database migrations",
+	Long:  "Apply pending database migrations. An existing database is backed up next to it first.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := migrateDB(mustDBPath()); err != nil {
+			die(err)
+		}
+	},
+}
+
+func init() {
+	dbCmd.AddCommand(dbStatusCmd, dbMigrateCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+func mustDBPath() string {
+	dbPath, err := db.Prepare()
+	if err != nil {
+		die(err)
+	}
+
+	return dbPath
+}
+
+// prints the location and migration status of the database without writing to it
+func showDBStatus() error {
+	dbPath, err := db.Path()
+	if err != nil {
+		return err
+	}
+	fmt.Println(i18n.Tf("database: %s", dbPath))
+
+	// only show the status, a missing database is created when pomo starts
+	if _, err := os.Stat(dbPath); errors.Is(err, fs.ErrNotExist) {
+		fmt.Println(i18n.T("the database does not exist yet"))
+		return nil
+	}
+
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	statuses, err := db.GetMigrationStatus(database)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\n"+i18n.T("VERSION\tNAME\tAPPLIED"))
+
+	for _, status := range statuses {
+		applied := i18n.T("pending")
+		if status.AppliedAt != nil {
+			applied = status.AppliedAt.Format(time.DateTime)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, applied)
+	}
+
+	return w.Flush()
+}
+
+// applies the pending migrations of the database at dbPath,
+// errors are returned instead of exiting so the database is closed first
+func migrateDB(dbPath string) error {
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	result, err := db.Migrate(database, dbPath)
+	if result.BackupPath != "" {
+		fmt.Println(i18n.Tf("backed up the database to %s", result.BackupPath))
+	}
+
+	for _, applied := range result.Applied {
+		fmt.Println(i18n.Tf("applied migration %d: %s", applied.Version, applied.Name))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(result.Applied) == 0 {
+		fmt.Println(i18n.Tf("database is up to date (version %d)", db.SchemaVersion()))
+	}
+
+	return nil
+}
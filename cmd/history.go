@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/Bahaaio/pomo/ui/reflection"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recent sessions with their notes and ratings",
+	Args:  cobra.NoArgs,
+	Example: `  pomo history         # Show the last 20 sessions
+  pomo history -n 50   # Show the last 50 sessions`,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit <= 0 {
+			fmt.Fprintln(os.Stderr, i18n.Tf("invalid limit: %d", limit))
+			die(nil)
+		}
+
+		database, err := db.Connect()
+		if err != nil {
+			die(err)
+		}
+		defer database.Close()
+
+		sessions, err := db.NewSessionRepo(database).GetRecentSessions(limit)
+		if err != nil {
+			die(err)
+		}
+
+		if len(sessions) == 0 {
+			fmt.Println(i18n.T("no sessions yet"))
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("STARTED\tTYPE\tDURATION\tOUTCOME\tRATING\tNOTE"))
+
+		for _, session := range sessions {
+			outcome := session.Outcome
+			if outcome == "" {
+				outcome = "-"
+			}
+
+			rating := reflection.FormatRating(session.Rating)
+			if rating == "" {
+				rating = "-"
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				session.StartedAt.Format("2006-01-02 15:04"),
+				session.Type,
+				session.Duration.Round(time.Second),
+				outcome,
+				rating,
+				session.Note,
+			)
+		}
+
+		_ = w.Flush()
+	},
+}
+
+func init() {
+	historyCmd.Flags().IntP("limit", "n", 20, "number of sessions to show")
+	rootCmd.AddCommand(historyCmd)
+}
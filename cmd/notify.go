@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Bahaaio/pomo/actions"
+	"github.com/Bahaaio/pomo/config"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/spf13/cobra"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Test notifiers and receive notifications of a remote pomo",
+	Example: `  pomo notify test                          # Send a test notification through every notifier
+  pomo notify listen ~/.pomo-notify.sock    # Show notifications sent to a forwarded socket`,
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a test notification through the configured notifiers",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		notification := config.Notification{
+			Enabled: true,
+			Title:   config.AppName,
+			Message: i18n.T("notifications work!"),
+		}
+
+		failed := false
+		for i, n := range config.C.Notifiers {
+			name := fmt.Sprintf("%d. %s", i+1, n.Type)
+
+			notifier, err := actions.NewNotifier(n, config.C.Terminal)
+			if err == nil {
+				err = notifier.Notify(notification)
+			}
+
+			if err != nil {
+				fmt.Println(i18n.Tf("%s: failed: %v", name, err))
+				failed = true
+				continue
+			}
+
+			fmt.Println(i18n.Tf("%s: sent", name))
+		}
+
+		if len(config.C.Notifiers) == 0 {
+			fmt.Println(i18n.T("no notifiers configured"))
+		}
+
+		if failed {
+			die(nil)
+		}
+	},
+}
+
+var notifyListenCmd = &cobra.Command{
+	Use:   "listen <socket>",
+	Short: "Show notifications sent to a unix socket on this desktop",
+	Long: `Listen on a unix socket and show the notifications socket notifiers send to it on this desktop.
+
+Forward the socket to the machine pomo runs on with SSH, e.g.
+  ssh -R /home/me/.pomo-notify.sock:$HOME/.pomo-notify.sock devbox
+and configure a socket notifier with the remote path there.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := args[0]
+
+		// a socket left behind by a listener that did not exit cleanly
+		if info, err := os.Stat(path); err == nil && info.Mode().Type() == fs.ModeSocket {
+			if conn, err := net.Dial("unix", path); err == nil {
+				_ = conn.Close()
+				die(errors.New(i18n.Tf("already listening on %s", path)))
+			}
+			_ = os.Remove(path)
+		}
+
+		listener, err := net.Listen("unix", path)
+		if err != nil {
+			die(err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		go func() {
+			<-ctx.Done()
+			// also removes the socket
+			_ = listener.Close()
+		}()
+
+		fmt.Println(i18n.Tf("listening on %s", path))
+
+		if err := actions.Serve(listener, printingNotifier{actions.Desktop{}}); err != nil && !errors.Is(err, net.ErrClosed) {
+			die(err)
+		}
+	},
+}
+
+// prints notifications before passing them on,
+// so they can be seen even if the desktop does not show them
+type printingNotifier struct {
+	actions.Notifier
+}
+
+func (n printingNotifier) Notify(notification config.Notification) error {
+	fmt.Printf("%s: %s\n", notification.Title, notification.Message)
+
+	err := n.Notifier.Notify(notification)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.Tf("failed to show notification: %v", err))
+	}
+
+	return err
+}
+
+func init() {
+	notifyCmd.AddCommand(notifyTestCmd, notifyListenCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
@@ -2,18 +2,26 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/Bahaaio/pomo/config"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/Bahaaio/pomo/paths"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gen2brain/beeep"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
-var version = "1.0.5"
+var (
+	version    = "1.0.5"
+	configFile string
+)
 
 var rootCmd = &cobra.Command{
 	Use:     "pomo [work duration] [break duration]",
@@ -29,6 +37,10 @@ desktop notifications when complete.`,
   pomo 45m 15m   # Start 45 minute work session with 15 minute break`,
 
 	Args: cobra.MaximumNArgs(2),
+	// load the config after flags are parsed
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		initConfig(cmd)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Println("rootCmd args:", args)
 		runTask(config.WorkTask, cmd)
@@ -41,17 +53,71 @@ func Execute() error {
 
 func init() {
 	initLogging()
-	initConfig()
 	beeep.AppName = config.AppName
+
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file to use (env: POMO_CONFIG)")
+	rootCmd.PersistentFlags().StringP("profile", "p", "", "config profile to use (env: POMO_PROFILE)")
+	rootCmd.PersistentFlags().String("db", "", "session database to use (env: POMO_DB)")
+
+	for _, flag := range []string{"profile", "db"} {
+		if err := viper.BindPFlag(flag, rootCmd.PersistentFlags().Lookup(flag)); err != nil {
+			log.Printf("failed to bind %s flag: %v", flag, err)
+		}
+	}
 }
 
-func initConfig() {
+func initConfig(cmd *cobra.Command) {
 	log.Println("initializing config")
 
-	config.Setup()
+	if configFile == "" {
+		configFile = os.Getenv(config.EnvPrefix + "_CONFIG")
+	}
+
+	config.Setup(configFile)
 	if err := config.LoadConfig(); err != nil {
+		var invalidConfig *config.InvalidConfigError
+
+		// the config commands report the problems themselves and must work to fix them
+		if isConfigCommand(cmd) {
+			log.Println("config has problems:", err)
+			useLanguage(config.C.Language)
+			return
+		}
+
+		// only warn about an invalid config if configured to do so
+		if errors.As(err, &invalidConfig) && !config.C.StrictConfig {
+			fmt.Fprintln(os.Stderr, "Warning:", err)
+			useLanguage(config.C.Language)
+			return
+		}
+
 		die(fmt.Errorf("could not load config: %w", err))
 	}
+
+	useLanguage(config.C.Language)
+}
+
+// reports whether cmd is the config command or one of its subcommands
+func isConfigCommand(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd == configCmd {
+			return true
+		}
+	}
+
+	return false
+}
+
+// translates the interface to language, an unsupported language keeps the current one
+func useLanguage(language string) {
+	locale, err := i18n.Load(language)
+	if err != nil {
+		log.Println("failed to load language:", err)
+		return
+	}
+
+	log.Printf("using language %s, weeks start on %s", locale.Language, locale.WeekStart)
+	i18n.Use(locale)
 }
 
 func initLogging() {
@@ -61,7 +127,12 @@ func initLogging() {
 		return
 	}
 
-	_, err := tea.LogToFile("debug.log", "")
+	// logs are state in the XDG sense, not user data
+	logPath, err := debugLogPath()
+	if err == nil {
+		_, err = tea.LogToFile(logPath, "")
+	}
+
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "failed to setup logging:", err)
 		os.Exit(1)
@@ -70,6 +141,20 @@ func initLogging() {
 	log.SetFlags(log.Ltime)
 }
 
+// returns the path of the debug log in the state directory, creating the directory
+func debugLogPath() (string, error) {
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, "debug.log"), nil
+}
+
 func die(err error) {
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsConfigCommand(t *testing.T) {
+	assert.True(t, isConfigCommand(configCmd))
+	assert.True(t, isConfigCommand(configValidateCmd), "subcommands of config should load the config leniently")
+	assert.False(t, isConfigCommand(rootCmd))
+	assert.False(t, isConfigCommand(statsCmd))
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/Bahaaio/pomo/actions"
 	"github.com/Bahaaio/pomo/config"
 	"github.com/Bahaaio/pomo/ui"
 	tea "github.com/charmbracelet/bubbletea"
@@ -15,15 +16,31 @@ import (
 func runTask(taskType config.TaskType, cmd *cobra.Command) {
 	task := taskType.GetTask()
 
-	if !parseArguments(cmd.Flags().Args(), task, &config.C.Break) {
+	args := cmd.Flags().Args()
+	if !parseArguments(args, task, &config.C.Break) {
 		_ = cmd.Usage()
 		die(nil)
 	}
 
+	// keep durations given on the command line when the config is reloaded
+	if len(args) > 0 {
+		taskType.PinDuration(task.Duration)
+	}
+	if len(args) > 1 {
+		config.BreakTask.PinDuration(config.C.Break.Duration)
+	}
+
 	log.Printf("starting %v session: %v", taskType.GetTask().Title, taskType.GetTask().Duration)
 
 	m := ui.NewModel(taskType, config.C.ASCIIArt, config.C.AskToContinue)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	// focus changes are reported for the focus idle detector,
+	// terminal notifications are written between frames
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithReportFocus(), tea.WithOutput(actions.TerminalOutput))
+
+	// apply config changes to the running timer
+	config.Watch(func(c config.Config, err error) {
+		p.Send(ui.ConfigReloadedMsg{Config: c, Err: err})
+	})
 
 	finalModel, err := p.Run()
 	if err != nil {
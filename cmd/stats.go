@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"os"
+
+	"github.com/Bahaaio/pomo/config"
+	"github.com/Bahaaio/pomo/db"
 	"github.com/Bahaaio/pomo/ui/stats"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
@@ -10,8 +15,27 @@ var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Args:  cobra.MaximumNArgs(0),
 	Short: "Display Pomodoro statistics and productivity metrics",
+	Long: `Display Pomodoro statistics and productivity metrics.
+
+Statistics are printed as plain text instead of the interactive view
+when --plain is given or the output isn't a terminal.`,
+	Example: `  pomo stats                          # Interactive view of this week
+  pomo stats --range month            # Start on this month
+  pomo stats --plain --range 30d      # Print the last 30 days
+  pomo stats --range 2026-W09         # Start on the ninth calendar week of 2026
+  pomo stats --json --range 2026-01   # Print January 2026 as JSON`,
 	Run: func(cmd *cobra.Command, args []string) {
-		m := stats.New()
+		statsRange := parseStatsRange(cmd)
+
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		plainOutput, _ := cmd.Flags().GetBool("plain")
+
+		if jsonOutput || plainOutput || !isatty.IsTerminal(os.Stdout.Fd()) {
+			printStats(statsRange, jsonOutput)
+			return
+		}
+
+		m := stats.New(statsRange, config.C.Stats)
 		p := tea.NewProgram(m, tea.WithAltScreen())
 
 		_, err := p.Run()
@@ -22,5 +46,46 @@ var statsCmd = &cobra.Command{
 }
 
 func init() {
+	statsCmd.Flags().StringP("range", "r", string(stats.WeekRange),
+		"range to show: day, week, month, year, a number of days like 30d, a date, a week like 2026-W09, a month like 2026-01, a year or from..to")
+	statsCmd.Flags().Bool("json", false, "print the statistics as JSON")
+	statsCmd.Flags().Bool("plain", false, "print the statistics as plain text")
+	statsCmd.MarkFlagsMutuallyExclusive("json", "plain")
+
 	rootCmd.AddCommand(statsCmd)
 }
+
+func parseStatsRange(cmd *cobra.Command) stats.Range {
+	value, _ := cmd.Flags().GetString("range")
+
+	statsRange, err := stats.ParseRange(value, db.NewCalendar(config.C.Stats))
+	if err != nil {
+		die(err)
+	}
+
+	return statsRange
+}
+
+// prints the statistics without the interactive view
+func printStats(statsRange stats.Range, jsonOutput bool) {
+	database, err := db.Connect()
+	if err != nil {
+		die(err)
+	}
+	defer database.Close()
+
+	report, err := stats.FetchReport(db.NewSessionRepo(database), statsRange)
+	if err != nil {
+		die(err)
+	}
+
+	if jsonOutput {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WritePlain(os.Stdout)
+	}
+
+	if err != nil {
+		die(err)
+	}
+}
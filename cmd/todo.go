@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/Bahaaio/pomo/ui/picker"
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/cobra"
+)
+
+var todoCmd = &cobra.Command{
+	Use:   "todo",
+	Short: "Manage the todos work sessions are spent on",
+	Long: `Manage the todos work sessions are spent on.
+
+Before a work session starts, pomo asks which open todo it is for
+and counts the finished pomodoros of each todo.`,
+	Example: `  pomo todo add "write the parser" -e 3   # Add a todo estimated at 3 pomodoros
+  pomo todo ls                            # List open todos
+  pomo todo done 1                        # Mark todo 1 as done`,
+}
+
+var todoAddCmd = &cobra.Command{
+	Use:   "add <title>",
+	Short: "Add a todo",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		title := strings.TrimSpace(strings.Join(args, " "))
+		if title == "" {
+			fmt.Fprintln(os.Stderr, i18n.T("the title must not be empty"))
+			die(nil)
+		}
+
+		estimate, _ := cmd.Flags().GetInt("estimate")
+		if estimate < 0 {
+			fmt.Fprintln(os.Stderr, i18n.Tf("invalid estimate: %d", estimate))
+			die(nil)
+		}
+
+		repo, database := mustTodoRepo()
+		defer database.Close()
+
+		id, err := repo.AddTodo(title, estimate)
+		if err != nil {
+			die(err)
+		}
+
+		fmt.Println(i18n.Tf("added todo %d: %s", id, title))
+	},
+}
+
+var todoListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List todos with their pomodoros",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+
+		repo, database := mustTodoRepo()
+		defer database.Close()
+
+		todos, err := repo.ListTodos(all)
+		if err != nil {
+			die(err)
+		}
+
+		if len(todos) == 0 {
+			fmt.Println(i18n.T("no todos, add one with: pomo todo add <title>"))
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("ID\tTITLE\tPOMODOROS\tVS ESTIMATE\tSTATUS"))
+
+		for _, todo := range todos {
+			status := i18n.T("open")
+			if todo.Done() {
+				status = i18n.T("done")
+			}
+
+			comparison := compareToEstimate(todo)
+			if comparison == "" {
+				comparison = "-"
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", todo.ID, todo.Title, picker.FormatPomodoros(todo), comparison, status)
+		}
+
+		_ = w.Flush()
+	},
+}
+
+var todoDoneCmd = &cobra.Command{
+	Use:   "done <id>",
+	Short: "Mark a todo as done",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.Tf("invalid todo id: %q", args[0]))
+			die(nil)
+		}
+
+		repo, database := mustTodoRepo()
+		defer database.Close()
+
+		if err = repo.CompleteTodo(id); errors.Is(err, sql.ErrNoRows) {
+			fmt.Fprintln(os.Stderr, i18n.Tf("no open todo with id %d", id))
+			die(nil)
+		} else if err != nil {
+			die(err)
+		}
+
+		todo, err := repo.GetTodo(id)
+		if err != nil {
+			die(err)
+		}
+
+		summary := picker.FormatPomodoros(todo) + " " + i18n.T("pomodoros")
+		if comparison := compareToEstimate(todo); comparison != "" {
+			summary += ", " + comparison
+		}
+
+		fmt.Println(i18n.Tf("done: %s (%s)", todo.Title, summary))
+	},
+}
+
+func init() {
+	todoAddCmd.Flags().IntP("estimate", "e", 0, "estimated number of pomodoros")
+	todoListCmd.Flags().BoolP("all", "a", false, "include done todos")
+
+	todoCmd.AddCommand(todoAddCmd, todoListCmd, todoDoneCmd)
+	rootCmd.AddCommand(todoCmd)
+}
+
+// returns the todo repository and its database, which the caller closes
+func mustTodoRepo() (*db.TodoRepo, *sqlx.DB) {
+	database, err := db.Connect()
+	if err != nil {
+		die(err)
+	}
+
+	return db.NewTodoRepo(database), database
+}
+
+// compares the finished pomodoros of a todo to its estimate,
+// empty if the todo isn't estimated
+func compareToEstimate(todo db.Todo) string {
+	if todo.Estimate == 0 {
+		return ""
+	}
+
+	switch diff := todo.Pomodoros - todo.Estimate; {
+	case diff > 0:
+		return i18n.Tf("%d over", diff)
+	case diff < 0:
+		return i18n.Tf("%d under", -diff)
+	default:
+		return i18n.T("on estimate")
+	}
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Bahaaio/pomo/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareToEstimate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		todo     db.Todo
+		expected string
+	}{
+		{"not estimated", db.Todo{Pomodoros: 3}, ""},
+		{"under", db.Todo{Pomodoros: 1, Estimate: 3}, "2 under"},
+		{"on estimate", db.Todo{Pomodoros: 3, Estimate: 3}, "on estimate"},
+		{"over", db.Todo{Pomodoros: 5, Estimate: 3}, "2 over"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, compareToEstimate(tc.todo))
+		})
+	}
+}
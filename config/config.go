@@ -4,51 +4,323 @@ package config
 
 import (
 	_ "embed"
-	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
-	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/Bahaaio/pomo/paths"
 	"github.com/Bahaaio/pomo/ui/ascii"
 	"github.com/Bahaaio/pomo/ui/colors"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 const (
-	AppName    = "pomo"
+	AppName    = paths.AppName
 	ConfigFile = "pomo.yaml"
+	EnvPrefix  = "POMO"
 )
 
 type Notification struct {
-	Enabled bool
-	Urgent  bool
-	Title   string
-	Message string
-	Icon    string
+	Enabled bool   `yaml:"enabled"`
+	Urgent  bool   `yaml:"urgent"`
+	Title   string `yaml:"title,omitempty"`
+	Message string `yaml:"message,omitempty"`
+	Icon    string `yaml:"icon,omitempty"`
 }
 
 type Task struct {
-	Title        string
-	Duration     time.Duration
-	Then         [][]string
-	Notification Notification
+	Title        string        `yaml:"title,omitempty"`
+	Duration     time.Duration `yaml:"duration"`
+	Then         [][]string    `yaml:"then,omitempty,flow"`
+	Notification Notification  `yaml:"notification"`
+
+	// Notifiers are the backends the notification of the task is sent through,
+	// empty for the notifiers of the config
+	Notifiers []Notifier `yaml:"notifiers,omitempty"`
+}
+
+// Localized returns a copy of the task with its title and notification texts
+// in the current language, the English defaults are translated and custom texts are kept.
+func (t Task) Localized() Task {
+	t.Title = i18n.T(t.Title)
+	t.Notification.Title = i18n.T(t.Notification.Title)
+	t.Notification.Message = i18n.T(t.Notification.Message)
+
+	return t
 }
 
 type ASCIIArt struct {
-	Enabled bool
-	Font    string
-	Color   string
+	Enabled bool   `yaml:"enabled"`
+	Font    string `yaml:"font,omitempty"`
+	Color   string `yaml:"color,omitempty"`
+}
+
+// WeekStartAuto starts weeks on the first day of the week of the language
+const WeekStartAuto = "auto"
+
+// Stats configures how sessions are grouped into days in the statistics.
+type Stats struct {
+	// Timezone is the IANA time zone days are computed in, empty for the local time zone
+	Timezone string `yaml:"timezone,omitempty"`
+
+	// DayStartsAt is the time of day a new day starts,
+	// sessions before it count toward the previous day
+	DayStartsAt time.Duration `yaml:"dayStartsAt"`
+
+	// SplitSessions apportions sessions across the days they span,
+	// instead of counting them toward the day they started
+	SplitSessions bool `yaml:"splitSessions"`
+
+	// WeekStartsOn is the weekday weeks start on, e.g. monday,
+	// WeekStartAuto uses the first day of the week of the language
+	WeekStartsOn string `yaml:"weekStartsOn,omitempty"`
+
+	// WeekNumbers labels weeks in the charts with their calendar week number
+	WeekNumbers bool `yaml:"weekNumbers"`
+
+	HeatMap HeatMap `yaml:"heatMap"`
+
+	Streak Streak `yaml:"streak"`
+}
+
+// how the intensity of heat map cells is decided
+const (
+	// HeatMapFixed compares the work of a day with 30m, 1h and 2h
+	HeatMapFixed = "fixed"
+	// HeatMapQuantile splits the days with work into four equally large groups
+	HeatMapQuantile = "quantile"
+)
+
+// MaxHeatMapMonths is the longest span of the heat map, a year
+const MaxHeatMapMonths = 12
+
+// HeatMap configures the activity heat map of the statistics.
+type HeatMap struct {
+	// Months is the number of months shown, fewer fit narrow terminals
+	Months int `yaml:"months"`
+
+	// Thresholds is HeatMapFixed or HeatMapQuantile
+	Thresholds string `yaml:"thresholds,omitempty"`
+}
+
+// Streak configures which days a streak requires work on.
+type Streak struct {
+	// RestDays are weekdays that neither extend nor break a streak, e.g. saturday
+	RestDays []string `yaml:"restDays,omitempty"`
+
+	// FreezesPerMonth is the number of missed days per month that don't break a streak
+	FreezesPerMonth int `yaml:"freezesPerMonth"`
+
+	// MinDuration is the work a day needs to count toward a streak
+	MinDuration time.Duration `yaml:"minDuration"`
+
+	// WeeklyMinDays is the number of counted days a week needs to count toward the weekly streak
+	WeeklyMinDays int `yaml:"weeklyMinDays"`
+}
+
+// what happens when a pause reaches its maximum length
+const (
+	PauseRemind = "remind"
+	PauseEnd    = "end"
+)
+
+// Pause limits how long a session may stay paused.
+type Pause struct {
+	// Max is the longest a pause may last, 0 for no limit
+	Max time.Duration `yaml:"max"`
+
+	// OnMax is what happens when a pause reaches Max,
+	// PauseRemind sends a notification and PauseEnd ends the session
+	OnMax string `yaml:"onMax,omitempty"`
+}
+
+// backends idle time is detected with
+const (
+	IdleAuto  = "auto"
+	IdleDBus  = "dbus"
+	IdleFocus = "focus"
+)
+
+// Idle configures when work sessions are paused automatically.
+type Idle struct {
+	// After is how long without input pauses a work session, 0 to never pause
+	After time.Duration `yaml:"after"`
+
+	// Backend is how idle time is detected,
+	// IdleAuto uses D-Bus where available and disables idle detection otherwise
+	Backend string `yaml:"backend,omitempty"`
+}
+
+// notifications sent through the terminal
+const (
+	TerminalNotificationNone = "none"
+	TerminalNotificationOSC9 = "osc9"
+	// OSC 777 is the notify extension of rxvt-unicode, also supported by e.g. foot and WezTerm
+	TerminalNotificationOSC777 = "osc777"
+)
+
+// Terminal configures notifications through the terminal,
+// they reach the desktop even over SSH.
+type Terminal struct {
+	// Bell rings the terminal bell when a session ends
+	Bell bool `yaml:"bell"`
+
+	// Notification is the escape sequence notifications are sent with, one of the TerminalNotification* values
+	Notification string `yaml:"notification,omitempty"`
+
+	// WindowTitle shows the remaining time in the terminal window title
+	WindowTitle bool `yaml:"windowTitle"`
+}
+
+// backends notifications are sent through
+const (
+	NotifierDesktop  = "desktop"
+	NotifierTerminal = "terminal"
+	NotifierSocket   = "socket"
+	NotifierNtfy     = "ntfy"
+	NotifierCommand  = "command"
+	NotifierBell     = "bell"
+	NotifierWebhook  = "webhook"
+	NotifierFile     = "file"
+)
+
+// NotifierTypes are the types of the built-in notifiers
+var NotifierTypes = []string{
+	NotifierDesktop, NotifierTerminal, NotifierBell, NotifierSocket,
+	NotifierNtfy, NotifierWebhook, NotifierCommand, NotifierFile,
+}
+
+var (
+	notifierTypesMu sync.RWMutex
+	notifierTypes   = map[string]bool{}
+)
+
+func init() {
+	for _, notifierType := range NotifierTypes {
+		notifierTypes[notifierType] = true
+	}
+}
+
+// RegisterNotifierType makes notifiers of a type pass validation,
+// registering a notifier backend with the actions package calls it.
+func RegisterNotifierType(notifierType string) {
+	notifierTypesMu.Lock()
+	defer notifierTypesMu.Unlock()
+
+	notifierTypes[notifierType] = true
+}
+
+// UnregisterNotifierType makes notifiers of a type fail validation again.
+func UnregisterNotifierType(notifierType string) {
+	notifierTypesMu.Lock()
+	defer notifierTypesMu.Unlock()
+
+	delete(notifierTypes, notifierType)
+}
+
+// returns the registered notifier types, sorted
+func registeredNotifierTypes() []string {
+	notifierTypesMu.RLock()
+	defer notifierTypesMu.RUnlock()
+
+	return slices.Sorted(maps.Keys(notifierTypes))
+}
+
+func isNotifierType(notifierType string) bool {
+	notifierTypesMu.RLock()
+	defer notifierTypesMu.RUnlock()
+
+	return notifierTypes[notifierType]
+}
+
+// Notifier configures a backend notifications are sent through,
+// only the fields of its type are used.
+type Notifier struct {
+	// Type is one of the Notifier* values
+	Type string `yaml:"type,omitempty"`
+
+	// Path is the unix socket socket notifiers write to,
+	// usually forwarded to the client with ssh -R,
+	// or the log file notifications are appended to by file notifiers
+	Path string `yaml:"path,omitempty"`
+
+	// URL is the topic ntfy notifiers publish to, e.g. https://ntfy.example.com/pomo,
+	// or the endpoint webhook notifiers post to
+	URL string `yaml:"url,omitempty"`
+
+	// Headers are sent with the requests of webhook notifiers, e.g. Authorization
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Token is the access token ntfy notifiers authenticate with, empty for none
+	Token string `yaml:"token,omitempty"`
+
+	// Command is run by command notifiers, {title} and {message} in its arguments are replaced
+	Command []string `yaml:"command,omitempty,flow"`
+}
+
+// WeekStart returns the weekday weeks start on.
+func (s Stats) WeekStart() (time.Weekday, error) {
+	if s.WeekStartsOn == "" || s.WeekStartsOn == WeekStartAuto {
+		return i18n.WeekStart(), nil
+	}
+
+	return ParseWeekday(s.WeekStartsOn)
+}
+
+// Location returns the time zone days are computed in.
+func (s Stats) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+
+	return time.LoadLocation(s.Timezone)
+}
+
+// Profile overlays work, break and ASCII art settings
+// on top of the base config when selected.
+type Profile struct {
+	Work     Task     `yaml:"work"`
+	Break    Task     `yaml:"break"`
+	ASCIIArt ASCIIArt `yaml:"asciiArt"`
 }
 
 type Config struct {
-	Work          Task
-	Break         Task
-	AskToContinue bool
-	ASCIIArt      ASCIIArt
+	Work          Task     `yaml:"work"`
+	Break         Task     `yaml:"break"`
+	AskToContinue bool     `yaml:"askToContinue"`
+	ASCIIArt      ASCIIArt `yaml:"asciiArt"`
+	Stats         Stats    `yaml:"stats"`
+	Pause         Pause    `yaml:"pause"`
+	Idle          Idle     `yaml:"idle"`
+	Terminal      Terminal `yaml:"terminal"`
+
+	// Notifiers are the backends notifications are sent through
+	Notifiers []Notifier `yaml:"notifiers,omitempty"`
+
+	// AskForNotes prompts for a note and rating after each work session
+	AskForNotes bool `yaml:"askForNotes"`
+
+	// Language is the language of the interface and default notifications, e.g. de,
+	// i18n.Auto detects it from the environment
+	Language string `yaml:"language,omitempty"`
+
+	// StrictConfig refuses to start with an invalid config instead of warning
+	StrictConfig bool `yaml:"strictConfig"`
+
+	// DB is the path of the session database, empty for the default location
+	DB string `yaml:"db,omitempty"`
+
+	// Profile is the name of the active profile, empty if none is selected
+	Profile  string             `yaml:"profile,omitempty"`
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
 }
 
 var (
@@ -56,13 +328,59 @@ var (
 	Icon []byte
 	C    Config
 
+	// Example is the annotated example config written by `pomo config init`
+	Example []byte
+
+	// set when the config file was given explicitly
+	requireConfigFile bool
+
+	// durations given on the command line, by config key
+	pinned = map[string]any{}
+
 	DefaultConfig = map[string]any{
 		"askToContinue": true,
+		"askForNotes":   false,
+		"language":      i18n.Auto,
+		"strictConfig":  true,
 		"asciiArt": map[string]any{
 			"enabled": true,
 			"font":    ascii.DefaultFont,
 			"color":   colors.TimerFg,
 		},
+		"pause": map[string]any{
+			"max":   time.Duration(0),
+			"onMax": PauseRemind,
+		},
+		"idle": map[string]any{
+			"after":   time.Duration(0),
+			"backend": IdleAuto,
+		},
+		"terminal": map[string]any{
+			"bell":         false,
+			"notification": TerminalNotificationNone,
+			"windowTitle":  false,
+		},
+		"notifiers": []map[string]any{
+			{"type": NotifierDesktop},
+			{"type": NotifierTerminal},
+		},
+		"stats": map[string]any{
+			"timezone":      "",
+			"dayStartsAt":   time.Duration(0),
+			"splitSessions": false,
+			"weekStartsOn":  WeekStartAuto,
+			"weekNumbers":   false,
+			"heatMap": map[string]any{
+				"months":     4,
+				"thresholds": HeatMapFixed,
+			},
+			"streak": map[string]any{
+				"restDays":        []string{},
+				"freezesPerMonth": 0,
+				"minDuration":     time.Duration(0),
+				"weeklyMinDays":   1,
+			},
+		},
 		"work": map[string]any{
 			"duration": 25 * time.Minute,
 			"title":    "work session",
@@ -86,16 +404,47 @@ var (
 	}
 )
 
-func Setup() {
-	if configFile, err := getConfigFile(); err == nil {
+// Setup configures where the config is read from and sets the default values.
+// If configFile is empty, the config file is searched for in the default locations.
+func Setup(configFile string) {
+	if configFile != "" {
+		log.Println("using config file from flag:", configFile)
+		viper.SetConfigFile(configFile)
+		requireConfigFile = true
+	} else if configFile, err := getConfigFile(); err == nil {
 		log.Println("using config file:", configFile)
 		viper.SetConfigFile(configFile)
 	} else {
 		log.Println("could not get user config dir:", err)
 	}
 
+	bindEnv(viper.GetViper())
+
 	log.Println("setting default config values")
-	setDefaults()
+	setDefaults(viper.GetViper())
+}
+
+// allows overriding any key with POMO_* environment variables,
+// nested keys are separated by underscores (e.g. POMO_WORK_DURATION)
+func bindEnv(v *viper.Viper) {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+}
+
+// FilePath returns the path of the config file in use,
+// or the default location if no config file was found.
+func FilePath() (string, error) {
+	if configFile := viper.ConfigFileUsed(); configFile != "" {
+		return configFile, nil
+	}
+
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, ConfigFile), nil
 }
 
 func LoadConfig() error {
@@ -103,31 +452,161 @@ func LoadConfig() error {
 
 	// fall back to defaults if no config file is found
 	if err := viper.ReadInConfig(); err != nil {
+		// an explicitly given config file must exist
+		if requireConfigFile {
+			return err
+		}
+
 		log.Println("no config file found, using defaults:", err)
 	} else {
 		log.Println("read config:", viper.ConfigFileUsed())
 	}
 
-	err := viper.Unmarshal(&C)
+	return decode(viper.GetViper(), &C)
+}
+
+// Watch watches the config file in use and calls onChange with the reloaded config
+// every time it changes, or with the error if it could not be loaded.
+// The global config and viper are left untouched, it is up to the caller to apply the changes.
+func Watch(onChange func(Config, error)) {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		log.Println("no config file to watch")
+		return
+	}
+
+	// the selected profile and pinned durations outlive reloads
+	overrides := map[string]any{"profile": viper.GetString("profile")}
+	maps.Copy(overrides, pinned)
+
+	// the watcher reads the file on its own goroutine, keep it away from the global viper
+	watcher := viper.New()
+	watcher.SetConfigFile(file)
+
+	watcher.OnConfigChange(func(event fsnotify.Event) {
+		log.Println("config file changed:", event)
+
+		var c Config
+		err := reload(file, overrides, &c)
+		onChange(c, err)
+	})
+
+	watcher.WatchConfig()
+}
+
+// reads file into c with a fresh viper, overrides take precedence over the file
+func reload(file string, overrides map[string]any, c *Config) error {
+	v := viper.New()
+	v.SetConfigFile(file)
+	bindEnv(v)
+	setDefaults(v)
+
+	for key, value := range overrides {
+		v.Set(key, value)
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		return err
+	}
+
+	return decode(v, c)
+}
+
+// decodes the config read by v into c after applying the selected profile
+func decode(v *viper.Viper, c *Config) error {
+	if err := applyProfile(v, v.GetString("profile")); err != nil {
+		return err
+	}
+
+	err := v.Unmarshal(c)
 	if err != nil {
+		// the schema reports more precise errors than the decoder
+		if file := v.ConfigFileUsed(); file != "" {
+			if problems, _ := ValidateFile(file); len(problems) > 0 {
+				return fmt.Errorf("%w\n%s", err, (&InvalidConfigError{Problems: problems}).Error())
+			}
+		}
+
 		return err
 	}
-	log.Println("Unmarshaled config:", C)
+	log.Println("Unmarshaled config:", *c)
 
-	if C.Work.Notification.Icon, err = expandPath(C.Work.Notification.Icon); err != nil {
+	if c.Work.Notification.Icon, err = expandPath(c.Work.Notification.Icon); err != nil {
 		log.Println("failed to expand Work Notification icon path:", err)
 	}
 
-	if C.Break.Notification.Icon, err = expandPath(C.Break.Notification.Icon); err != nil {
+	if c.Break.Notification.Icon, err = expandPath(c.Break.Notification.Icon); err != nil {
 		log.Println("failed to expand Break Notification icon path:", err)
 	}
 
+	if c.DB, err = expandPath(c.DB); err != nil {
+		log.Println("failed to expand database path:", err)
+	}
+
+	for _, notifiers := range [][]Notifier{c.Notifiers, c.Work.Notifiers, c.Break.Notifiers} {
+		for i := range notifiers {
+			if notifiers[i].Path, err = expandPath(notifiers[i].Path); err != nil {
+				log.Println("failed to expand notifier path:", err)
+			}
+		}
+	}
+
+	if problems := validate(v.ConfigFileUsed(), *c); len(problems) > 0 {
+		return &InvalidConfigError{Problems: problems}
+	}
+
+	return nil
+}
+
+// profileKeys are the top-level keys a profile is allowed to overlay
+var profileKeys = []string{"work", "break", "asciiArt"}
+
+// merges the settings of the named profile on top of the loaded config
+func applyProfile(v *viper.Viper, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	// viper keys are case-insensitive
+	name = strings.ToLower(name)
+	profileKey := "profiles." + name
+
+	if !v.IsSet(profileKey) {
+		return fmt.Errorf("profile %q not found", name)
+	}
+
+	log.Println("applying profile:", name)
+
+	overlay := make(map[string]any)
+	for _, key := range profileKeys {
+		if value := v.Get(profileKey + "." + key); value != nil {
+			overlay[key] = value
+		}
+	}
+
+	if err := v.MergeConfigMap(overlay); err != nil {
+		return err
+	}
+
+	// record the normalized profile name
+	v.Set("profile", name)
 	return nil
 }
 
-func setDefaults() {
+// ParseWeekday parses a weekday name such as "saturday".
+func ParseWeekday(name string) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(strings.TrimSpace(name), weekday.String()) {
+			return weekday, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown weekday %q", name)
+}
+
+func setDefaults(v *viper.Viper) {
 	for key, value := range DefaultConfig {
-		viper.SetDefault(key, value)
+		v.SetDefault(key, value)
 	}
 }
 
@@ -154,39 +633,18 @@ func getConfigFile() (string, error) {
 		return ConfigFile, nil
 	}
 
-	// check config directory
-	var configDir string
-	if configDir, err = getConfigDir(); err == nil {
-		configPath := filepath.Join(configDir, ConfigFile)
+	// check the config directory, then the one used before XDG_CONFIG_HOME was honored
+	for _, getDir := range []func() (string, error){paths.ConfigDir, paths.LegacyConfigDir} {
+		var configDir string
+		if configDir, err = getDir(); err != nil {
+			continue
+		}
 
-		if _, err := os.Stat(configPath); err == nil {
+		configPath := filepath.Join(configDir, ConfigFile)
+		if _, err = os.Stat(configPath); err == nil {
 			return configPath, nil
 		}
 	}
 
 	return "", fmt.Errorf("config file not found: %w", err)
 }
-
-// returns the config directory for the app
-func getConfigDir() (string, error) {
-	var dir string
-
-	// on linux and macOS, use ~/.config
-	if runtime.GOOS == "linux" || runtime.GOOS == "darwin" {
-		dir = os.Getenv("HOME")
-		if dir == "" {
-			return "", errors.New("$HOME is not defined")
-		}
-
-		dir = filepath.Join(dir, ".config")
-	} else {
-		// on other OSes, use the standard user config directory
-		var err error
-		dir, err = os.UserConfigDir()
-		if err != nil {
-			return "", err
-		}
-	}
-
-	return filepath.Join(dir, AppName), nil
-}
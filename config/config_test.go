@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Bahaaio/pomo/i18n"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -44,6 +45,62 @@ func TestLoadConfigDefaults(t *testing.T) {
 	assertConfigMatches(t, defaults, C)
 }
 
+func TestLoadConfigNotifiers(t *testing.T) {
+	setupViper()
+	writeAndLoadConfig(t, `
+notifiers:
+  - type: socket
+    path: ~/.pomo-notify.sock
+  - type: command
+    command: [notify-send, "{title}", "{message}"]
+work:
+  notifiers:
+    - type: webhook
+      url: https://example.com/hooks/pomo
+      headers:
+        Authorization: Bearer secret
+    - type: file
+      path: ~/pomo-notifications.log
+`)
+
+	homeDir, err := os.UserHomeDir()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Notifier{
+		{Type: NotifierSocket, Path: filepath.Join(homeDir, ".pomo-notify.sock")},
+		{Type: NotifierCommand, Command: []string{"notify-send", "{title}", "{message}"}},
+	}, C.Notifiers)
+
+	assert.Equal(t, []Notifier{
+		{Type: NotifierWebhook, URL: "https://example.com/hooks/pomo", Headers: map[string]string{"authorization": "Bearer secret"}},
+		{Type: NotifierFile, Path: filepath.Join(homeDir, "pomo-notifications.log")},
+	}, C.Work.Notifiers)
+	assert.Empty(t, C.Break.Notifiers, "tasks without notifiers use the top-level ones")
+}
+
+func TestLoadConfigLanguage(t *testing.T) {
+	setupViper()
+	writeAndLoadConfig(t, `
+language: de
+break:
+  title: Kaffee
+`)
+
+	assert.Equal(t, "de", C.Language)
+	assert.Equal(t, "work session", C.Work.Title, "loaded titles should not be translated")
+
+	locale, err := i18n.Load(C.Language)
+	assert.NoError(t, err)
+
+	previous := i18n.Current()
+	i18n.Use(locale)
+	t.Cleanup(func() { i18n.Use(previous) })
+
+	assert.Equal(t, "Arbeitsphase", C.Work.Localized().Title, "default titles should be translated")
+	assert.Equal(t, "Zeit für eine Pause!", C.Work.Localized().Notification.Message, "default messages should be translated")
+	assert.Equal(t, "Kaffee", C.Break.Localized().Title, "custom titles should be kept")
+}
+
 func TestLoadConfigPartialUpdate(t *testing.T) {
 	partialConfig := `
 askToContinue: true
@@ -164,6 +221,187 @@ break:
 	assert.Equal(t, "/abs/path/break-icon.png", C.Break.Notification.Icon, "Break notification icon should match")
 }
 
+func TestLoadConfigProfile(t *testing.T) {
+	configYAML := `
+work:
+  duration: 25m
+  title: work session
+asciiArt:
+  font: ansi
+profiles:
+  deep:
+    work:
+      duration: 50m
+    asciiArt:
+      color: "#FF0000"
+`
+
+	setupViper()
+	viper.Set("profile", "Deep")
+	writeAndLoadConfig(t, configYAML)
+
+	assert.Equal(t, "deep", C.Profile, "Profile name should be normalized")
+	assert.Equal(t, 50*time.Minute, C.Work.Duration, "Profile should override work duration")
+	assert.Equal(t, "work session", C.Work.Title, "Base work title should be kept")
+	assert.Equal(t, "ansi", C.ASCIIArt.Font, "Base font should be kept")
+	assert.Equal(t, "#FF0000", C.ASCIIArt.Color, "Profile should override color")
+	assert.Equal(t, 5*time.Minute, C.Break.Duration, "Default break duration should be kept")
+}
+
+func TestLoadConfigUnknownProfile(t *testing.T) {
+	setupViper()
+	viper.Set("profile", "missing")
+
+	tempDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(tempDir, ConfigFile), []byte("askToContinue: true\n"), 0o644)
+	assert.NoError(t, err, "Failed to write test config")
+
+	viper.AddConfigPath(tempDir)
+	assert.Error(t, LoadConfig(), "Loading an unknown profile should fail")
+}
+
+func TestReload(t *testing.T) {
+	setupViper()
+
+	configFile := filepath.Join(t.TempDir(), ConfigFile)
+	configYAML := `
+askToContinue: false
+profiles:
+  deep:
+    work:
+      duration: 50m
+`
+	err := os.WriteFile(configFile, []byte(configYAML), 0o644)
+	assert.NoError(t, err, "Failed to write test config")
+
+	var c Config
+	overrides := map[string]any{"profile": "deep", "break.duration": 10 * time.Minute}
+	assert.NoError(t, reload(configFile, overrides, &c), "Failed to reload config")
+
+	assert.False(t, c.AskToContinue, "Reloaded config should be read from the file")
+	assert.Equal(t, 50*time.Minute, c.Work.Duration, "Selected profile should be applied")
+	assert.Equal(t, 10*time.Minute, c.Break.Duration, "Pinned duration should be kept")
+	assert.Empty(t, viper.GetString("profile"), "Global viper should be left untouched")
+	assert.Empty(t, viper.ConfigFileUsed(), "Global viper should be left untouched")
+}
+
+func TestReloadInvalid(t *testing.T) {
+	setupViper()
+
+	configFile := filepath.Join(t.TempDir(), ConfigFile)
+	err := os.WriteFile(configFile, []byte("pause:\n  onMax: never\n"), 0o644)
+	assert.NoError(t, err, "Failed to write test config")
+
+	var c Config
+	var invalid *InvalidConfigError
+	assert.ErrorAs(t, reload(configFile, nil, &c), &invalid, "Reloading an invalid config should fail")
+	assert.NotEmpty(t, invalid.Problems, "Problems should be reported")
+	assert.Equal(t, configFile, invalid.Problems[0].File, "Problems should point to the reloaded file")
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	configYAML := `
+askToContinue: true
+asciiArt:
+  font: comic
+work:
+  duration: 0s
+  then:
+    - []
+brake:
+  duration: 5m
+`
+
+	setupViper()
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, ConfigFile)
+	assert.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0o644))
+	viper.AddConfigPath(tempDir)
+
+	err := LoadConfig()
+
+	var invalidConfig *InvalidConfigError
+	if !assert.ErrorAs(t, err, &invalidConfig) {
+		return
+	}
+
+	// all problems are reported with their location
+	locations := make(map[string]int)
+	for _, problem := range invalidConfig.Problems {
+		assert.Equal(t, configFile, problem.File)
+		locations[problem.Key] = problem.Line
+	}
+
+	assert.Equal(t, map[string]int{
+		"asciiArt.font": 4,
+		"work.duration": 6,
+		"work.then[0]":  8,
+		"brake":         9,
+	}, locations)
+
+	// the config is still loaded
+	assert.True(t, C.AskToContinue)
+}
+
+func TestConfigValidate(t *testing.T) {
+	c := getDefaultConfig()
+	assert.Empty(t, c.Validate(), "Default config should be valid")
+
+	c.Work.Notifiers = []Notifier{{Type: NotifierBell}, {Type: NotifierFile}}
+	c.Break.Duration = -time.Minute
+	c.ASCIIArt.Color = "purple"
+	c.Stats.Timezone = "Mars/Olympus_Mons"
+	c.Stats.DayStartsAt = 25 * time.Hour
+	c.Stats.WeekStartsOn = "someday"
+	c.Stats.HeatMap.Months = 13
+	c.Stats.HeatMap.Thresholds = "median"
+	c.Stats.Streak.RestDays = []string{"saturday", "caturday"}
+	c.Stats.Streak.WeeklyMinDays = 0
+	c.Pause.Max = -time.Minute
+	c.Pause.OnMax = "snooze"
+	c.Idle.Backend = "webcam"
+	c.Terminal.Notification = "osc99"
+	c.Language = "klingon"
+	c.Notifiers = append(c.Notifiers,
+		Notifier{Type: "pager"},
+		Notifier{Type: NotifierSocket},
+		Notifier{Type: NotifierNtfy, URL: "ntfy.example.com/pomo"},
+		Notifier{Type: NotifierCommand, Command: []string{" "}},
+	)
+
+	var keys []string
+	for _, problem := range c.Validate() {
+		keys = append(keys, problem.Key)
+	}
+
+	assert.Equal(t, []string{"work.notifiers[1].path", "break.duration", "asciiArt.color", "stats.timezone", "stats.dayStartsAt", "stats.weekStartsOn",
+		"stats.heatMap.months", "stats.heatMap.thresholds", "stats.streak.restDays[1]", "stats.streak.weeklyMinDays", "pause.max", "pause.onMax",
+		"idle.backend", "terminal.notification", "language",
+		"notifiers[2].type", "notifiers[3].path", "notifiers[4].url", "notifiers[5].command",
+	}, keys)
+}
+
+func TestStatsWeekStart(t *testing.T) {
+	testCases := []struct {
+		weekStartsOn string
+		want         time.Weekday
+	}{
+		{WeekStartAuto, i18n.WeekStart()},
+		{"", i18n.WeekStart()},
+		{"monday", time.Monday},
+		{"Saturday", time.Saturday},
+	}
+
+	for _, tt := range testCases {
+		got, err := Stats{WeekStartsOn: tt.weekStartsOn}.WeekStart()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, "week start of %q", tt.weekStartsOn)
+	}
+
+	_, err := Stats{WeekStartsOn: "someday"}.WeekStart()
+	assert.Error(t, err)
+}
+
 func TestExpandPath(t *testing.T) {
 	homeDir, err := os.UserHomeDir()
 	assert.NoError(t, err, "Failed to get home directory")
@@ -240,7 +478,7 @@ func setupViper() {
 	viper.SetConfigName(AppName)
 	viper.SetConfigType("yaml")
 
-	setDefaults()
+	setDefaults(viper.GetViper())
 }
 
 func writeAndLoadConfig(t *testing.T, config string) {
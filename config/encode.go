@@ -0,0 +1,29 @@
+package config
+
+import (
+	"bytes"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Marshal encodes the effective config as YAML,
+// using the same key names and duration format as the config file.
+func Marshal(c Config) ([]byte, error) {
+	// profiles are already merged into the effective config
+	c.Profiles = nil
+
+	var buffer bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(c); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,388 @@
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+//go:embed schema.json
+var schemaJSON []byte
+
+// ValidationError describes a single problem in the config,
+// File and Line are only set when the problem can be located in a config file.
+type ValidationError struct {
+	File    string
+	Key     string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	key := e.Key
+	if key == "" {
+		key = "<root>"
+	}
+
+	location := e.File
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+	}
+
+	if location == "" {
+		return fmt.Sprintf("%s: %s", key, e.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", location, key, e.Message)
+}
+
+// location of the embedded schema in the compiler
+const schemaURL = "schema.json"
+
+// compiledSchema returns the embedded schema, compiled on first use
+var compiledSchema = sync.OnceValues(func() (*jsonschema.Schema, error) {
+	document, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, document); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile(schemaURL)
+})
+
+// ValidateFile validates the YAML config file at path against the embedded schema.
+// The returned error is only set if the file could not be read or parsed.
+func ValidateFile(path string) ([]ValidationError, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	problems, err := ValidateYAML(content)
+	for i := range problems {
+		problems[i].File = path
+	}
+
+	return problems, err
+}
+
+// ValidateYAML validates YAML config content against the embedded schema.
+func ValidateYAML(content []byte) ([]ValidationError, error) {
+	schema, err := compiledSchema()
+	if err != nil {
+		return nil, fmt.Errorf("invalid embedded schema: %w", err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, err
+	}
+
+	// an empty file is a valid config
+	if len(node.Content) == 0 {
+		return nil, nil
+	}
+
+	d := document{values: make(map[string]value)}
+	instance := d.convert(node.Content[0], schema, nil, "")
+
+	var invalid *jsonschema.ValidationError
+	if err := schema.Validate(instance); !errors.As(err, &invalid) {
+		return nil, err
+	}
+
+	d.collect(invalid)
+
+	// report problems in the order they appear in the file
+	sort.SliceStable(d.problems, func(i, j int) bool {
+		a, b := d.problems[i], d.problems[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return d.problems, nil
+}
+
+// value is a value of the config file, with its key and position
+type value struct {
+	key    string     // e.g. notifiers[1].type, keys spelled like in the file
+	keyDef *yaml.Node // the key of mapping entries, nil otherwise
+	node   *yaml.Node
+	schema *jsonschema.Schema
+}
+
+// document maps the values of a config file to the errors of the schema library
+type document struct {
+	values   map[string]value // by instance location
+	problems []ValidationError
+}
+
+// converts node to a JSON value and records where its values are,
+// keys are spelled like in the schema as viper matches them case-insensitively
+func (d *document) convert(node *yaml.Node, schema *jsonschema.Schema, location []string, key string) any {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	schema = resolve(schema)
+
+	v := d.values[pointer(location)]
+	v.key, v.node, v.schema = key, node, schema
+	d.values[pointer(location)] = v
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		object := make(map[string]any)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+			name, property := lookupProperty(schema, keyNode.Value)
+			childLocation := append(slices.Clone(location), name)
+
+			d.values[pointer(childLocation)] = value{keyDef: keyNode}
+			object[name] = d.convert(valueNode, property, childLocation, joinKey(key, keyNode.Value))
+		}
+
+		return object
+
+	case yaml.SequenceNode:
+		var items *jsonschema.Schema
+		if schema != nil {
+			items, _ = schema.Items.(*jsonschema.Schema)
+		}
+
+		array := make([]any, len(node.Content))
+		for i, item := range node.Content {
+			array[i] = d.convert(item, items, append(slices.Clone(location), strconv.Itoa(i)), fmt.Sprintf("%s[%d]", key, i))
+		}
+
+		return array
+
+	default:
+		return convertScalar(node, schema)
+	}
+}
+
+func convertScalar(node *yaml.Node, schema *jsonschema.Schema) any {
+	switch node.ShortTag() {
+	case "!!bool":
+		var b bool
+		if node.Decode(&b) == nil {
+			return b
+		}
+	case "!!int", "!!float":
+		// durations, colors and paths are all strings, accept numbers for them
+		if schema != nil && schema.Types != nil && slices.Equal(schema.Types.ToStrings(), []string{"string"}) {
+			return node.Value
+		}
+
+		var number json.Number
+		if node.Decode(&number) == nil {
+			return number
+		}
+	case "!!null":
+		return nil
+	}
+
+	return node.Value
+}
+
+// records the innermost errors of err as problems
+func (d *document) collect(err *jsonschema.ValidationError) {
+	for _, cause := range err.Causes {
+		d.collect(cause)
+	}
+
+	if len(err.Causes) > 0 {
+		return
+	}
+
+	at := d.values[pointer(err.InstanceLocation)]
+
+	if additional, ok := err.ErrorKind.(*kind.AdditionalProperties); ok {
+		for _, name := range additional.Properties {
+			property := d.values[pointer(append(slices.Clone(err.InstanceLocation), name))]
+			d.fail(property.keyDef, property.key, "unknown key"+suggestKey(at.schema, name))
+		}
+		return
+	}
+
+	d.fail(at.node, at.key, describeError(err.ErrorKind, at.node))
+}
+
+func (d *document) fail(node *yaml.Node, key, message string) {
+	problem := ValidationError{Key: key, Message: message}
+	if node != nil {
+		problem.Line, problem.Column = node.Line, node.Column
+	}
+
+	d.problems = append(d.problems, problem)
+}
+
+var printer = message.NewPrinter(language.English)
+
+func describeError(errorKind jsonschema.ErrorKind, node *yaml.Node) string {
+	switch k := errorKind.(type) {
+	case *kind.Type:
+		return fmt.Sprintf("expected %s, got %s", strings.Join(k.Want, " or "), describeNode(node))
+	case *kind.Enum:
+		return fmt.Sprintf("%q is not one of %s", node.Value, formatEnum(k.Want))
+	case *kind.Pattern:
+		return fmt.Sprintf("%q does not match pattern %s", k.Got, k.Want)
+	case *kind.MinItems:
+		return fmt.Sprintf("expected at least %d item(s), got %d", k.Want, k.Got)
+	case *kind.Minimum:
+		return fmt.Sprintf("%v is less than the minimum of %v", float(k.Got), float(k.Want))
+	case *kind.Maximum:
+		return fmt.Sprintf("%v is greater than the maximum of %v", float(k.Got), float(k.Want))
+	default:
+		return errorKind.LocalizedString(printer)
+	}
+}
+
+func float(r *big.Rat) float64 {
+	f, _ := r.Float64()
+	return f
+}
+
+// returns the schema a reference points to
+func resolve(schema *jsonschema.Schema) *jsonschema.Schema {
+	for schema != nil && schema.Ref != nil {
+		schema = schema.Ref
+	}
+
+	return schema
+}
+
+// returns the spelling of the property name in the schema and its schema,
+// keys are matched case-insensitively, like viper does
+func lookupProperty(schema *jsonschema.Schema, name string) (string, *jsonschema.Schema) {
+	if schema == nil {
+		return name, nil
+	}
+
+	if property, ok := schema.Properties[name]; ok {
+		return name, property
+	}
+
+	for key, property := range schema.Properties {
+		if strings.EqualFold(key, name) {
+			return key, property
+		}
+	}
+
+	additional, _ := schema.AdditionalProperties.(*jsonschema.Schema)
+	return name, additional
+}
+
+// returns the key of an instance location in the values of a document
+func pointer(location []string) string {
+	return strings.Join(location, "\x00")
+}
+
+// returns a hint for the closest known key
+func suggestKey(schema *jsonschema.Schema, name string) string {
+	if schema == nil {
+		return ""
+	}
+
+	best, bestDistance := "", 3 // only suggest close matches
+
+	for key := range schema.Properties {
+		distance := levenshtein(strings.ToLower(key), strings.ToLower(name))
+		if distance < bestDistance || (distance == bestDistance && key < best) {
+			best, bestDistance = key, distance
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+
+	switch node.ShortTag() {
+	case "!!bool":
+		return "boolean"
+	case "!!int", "!!float":
+		return "number"
+	case "!!null":
+		return "null"
+	default:
+		return fmt.Sprintf("string %q", node.Value)
+	}
+}
+
+func formatEnum(enum []any) string {
+	options := make([]string, 0, len(enum))
+	for _, option := range enum {
+		options = append(options, fmt.Sprint(option))
+	}
+	sort.Strings(options)
+
+	return "[" + strings.Join(options, ", ") + "]"
+}
+
+func joinKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+
+	return parent + "." + key
+}
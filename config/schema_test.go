@@ -0,0 +1,154 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateYAML(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		wantKeys []string
+	}{
+		{
+			name:  "empty config is valid",
+			input: "",
+		},
+		{
+			name: "valid config",
+			input: `
+askToContinue: true
+asciiArt:
+  font: rebel
+  color: "#FF0000"
+work:
+  duration: 1h30m
+  then:
+    - [echo, done]
+profiles:
+  deep:
+    work:
+      duration: 50m
+`,
+		},
+		{
+			name: "keys are case-insensitive",
+			input: `
+AskToContinue: false
+`,
+		},
+		{
+			name: "unknown keys",
+			input: `
+askToContinu: true
+work:
+  durration: 25m
+`,
+			wantKeys: []string{"askToContinu", "work.durration"},
+		},
+		{
+			name: "invalid values",
+			input: `
+askToContinue: yes please
+asciiArt:
+  font: comic
+  color: red
+work:
+  duration: 25 minutes
+`,
+			wantKeys: []string{"askToContinue", "asciiArt.font", "asciiArt.color", "work.duration"},
+		},
+		{
+			name: "empty then command",
+			input: `
+break:
+  then:
+    - []
+`,
+			wantKeys: []string{"break.then[0]"},
+		},
+		{
+			name: "invalid notifiers",
+			input: `
+notifiers:
+  - type: desktop
+  - type: [desktop]
+  - type: ntfy
+    topic: pomo
+work:
+  notifiers:
+    - type: webhook
+      headers:
+        Authorization: true
+`,
+			wantKeys: []string{"notifiers[1].type", "notifiers[2].topic", "work.notifiers[0].headers.Authorization"},
+		},
+		{
+			name: "invalid profile",
+			input: `
+profiles:
+  deep:
+    askToContinue: false
+`,
+			wantKeys: []string{"profiles.deep.askToContinue"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := ValidateYAML([]byte(tt.input))
+			assert.NoError(t, err)
+
+			var keys []string
+			for _, problem := range problems {
+				keys = append(keys, problem.Key)
+				assert.Positive(t, problem.Line, "error should have a line number")
+			}
+
+			assert.Equal(t, tt.wantKeys, keys)
+		})
+	}
+}
+
+func TestValidateYAMLSuggestsKey(t *testing.T) {
+	problems, err := ValidateYAML([]byte("wrok:\n  duration: 25m\n"))
+	assert.NoError(t, err)
+	assert.Len(t, problems, 1)
+
+	assert.Equal(t, 1, problems[0].Line)
+	assert.Contains(t, problems[0].Error(), `did you mean "work"?`)
+}
+
+func TestMarshal(t *testing.T) {
+	setupViper()
+	writeAndLoadConfig(t, "work:\n  duration: 45m\n  then:\n    - [echo, hi]\n")
+
+	out, err := Marshal(C)
+	assert.NoError(t, err)
+
+	// the output should be a valid config itself
+	problems, err := ValidateYAML(out)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	assert.Contains(t, string(out), "askToContinue: true")
+	assert.Contains(t, string(out), "asciiArt:")
+	assert.Contains(t, string(out), "duration: 45m0s")
+	assert.Contains(t, string(out), "then: [[echo, hi]]")
+	assert.Contains(t, string(out), "notifiers:\n  - type: desktop\n  - type: terminal\n")
+}
+
+func TestSchemaLanguages(t *testing.T) {
+	schema, err := compiledSchema()
+	assert.NoError(t, err)
+
+	want := []any{i18n.Auto}
+	for _, language := range i18n.Languages() {
+		want = append(want, language)
+	}
+
+	assert.ElementsMatch(t, want, schema.Properties["language"].Enum.Values, "schema should list every catalog")
+}
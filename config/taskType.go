@@ -1,5 +1,11 @@
 package config
 
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
 type TaskType int
 
 const (
@@ -24,3 +30,19 @@ func (t TaskType) Opposite() TaskType {
 func (t TaskType) String() string {
 	return t.GetTask().Title
 }
+
+// Key returns the config key of the task.
+func (t TaskType) Key() string {
+	if t == BreakTask {
+		return "break"
+	}
+	return "work"
+}
+
+// PinDuration keeps the given duration for the task
+// even if the config file is reloaded.
+func (t TaskType) PinDuration(duration time.Duration) {
+	t.GetTask().Duration = duration
+	viper.Set(t.Key()+".duration", duration)
+	pinned[t.Key()+".duration"] = duration
+}
@@ -0,0 +1,363 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/Bahaaio/pomo/ui/ascii"
+	"github.com/Bahaaio/pomo/ui/colors"
+	"go.yaml.in/yaml/v3"
+)
+
+// InvalidConfigError is returned by [LoadConfig] when the loaded config has problems.
+// The config is still loaded, so callers may choose to only warn about it.
+type InvalidConfigError struct {
+	Problems []ValidationError
+}
+
+func (e *InvalidConfigError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("found %d problem(s) in config:", len(e.Problems)))
+
+	for _, problem := range e.Problems {
+		lines = append(lines, "  "+problem.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks the config for values pomo cannot work with.
+func (c Config) Validate() []ValidationError {
+	var problems []ValidationError
+
+	problems = append(problems, validateTask("work", c.Work)...)
+	problems = append(problems, validateTask("break", c.Break)...)
+
+	if c.ASCIIArt.Enabled && !ascii.FontExists(c.ASCIIArt.Font) {
+		problems = append(problems, ValidationError{
+			Key:     "asciiArt.font",
+			Message: fmt.Sprintf("unknown font %q", c.ASCIIArt.Font),
+		})
+	}
+
+	if !colors.IsValid(c.ASCIIArt.Color) {
+		problems = append(problems, ValidationError{
+			Key:     "asciiArt.color",
+			Message: fmt.Sprintf("invalid color %q, expected a hex color like #5A56E0 or %q", c.ASCIIArt.Color, colors.None),
+		})
+	}
+
+	if _, err := c.Stats.Location(); err != nil {
+		problems = append(problems, ValidationError{
+			Key:     "stats.timezone",
+			Message: fmt.Sprintf("unknown time zone %q", c.Stats.Timezone),
+		})
+	}
+
+	if c.Stats.DayStartsAt < 0 || c.Stats.DayStartsAt >= 24*time.Hour {
+		problems = append(problems, ValidationError{
+			Key:     "stats.dayStartsAt",
+			Message: fmt.Sprintf("day must start between 0h and 24h, got %v", c.Stats.DayStartsAt),
+		})
+	}
+
+	if _, err := c.Stats.WeekStart(); err != nil {
+		problems = append(problems, ValidationError{
+			Key:     "stats.weekStartsOn",
+			Message: fmt.Sprintf("expected %q or a weekday, got %q", WeekStartAuto, c.Stats.WeekStartsOn),
+		})
+	}
+
+	if c.Stats.HeatMap.Months < 1 || c.Stats.HeatMap.Months > MaxHeatMapMonths {
+		problems = append(problems, ValidationError{
+			Key:     "stats.heatMap.months",
+			Message: fmt.Sprintf("must be between 1 and %d, got %d", MaxHeatMapMonths, c.Stats.HeatMap.Months),
+		})
+	}
+
+	if c.Stats.HeatMap.Thresholds != HeatMapFixed && c.Stats.HeatMap.Thresholds != HeatMapQuantile {
+		problems = append(problems, ValidationError{
+			Key:     "stats.heatMap.thresholds",
+			Message: fmt.Sprintf("expected %q or %q, got %q", HeatMapFixed, HeatMapQuantile, c.Stats.HeatMap.Thresholds),
+		})
+	}
+
+	problems = append(problems, validateStreak("stats.streak", c.Stats.Streak)...)
+
+	if c.Pause.Max < 0 {
+		problems = append(problems, ValidationError{
+			Key:     "pause.max",
+			Message: fmt.Sprintf("must not be negative, got %v", c.Pause.Max),
+		})
+	}
+
+	if c.Pause.OnMax != PauseRemind && c.Pause.OnMax != PauseEnd {
+		problems = append(problems, ValidationError{
+			Key:     "pause.onMax",
+			Message: fmt.Sprintf("expected %q or %q, got %q", PauseRemind, PauseEnd, c.Pause.OnMax),
+		})
+	}
+
+	if c.Idle.After < 0 {
+		problems = append(problems, ValidationError{
+			Key:     "idle.after",
+			Message: fmt.Sprintf("must not be negative, got %v", c.Idle.After),
+		})
+	}
+
+	if c.Idle.Backend != IdleAuto && c.Idle.Backend != IdleDBus && c.Idle.Backend != IdleFocus {
+		problems = append(problems, ValidationError{
+			Key:     "idle.backend",
+			Message: fmt.Sprintf("expected %q, %q or %q, got %q", IdleAuto, IdleDBus, IdleFocus, c.Idle.Backend),
+		})
+	}
+
+	switch c.Terminal.Notification {
+	case TerminalNotificationNone, TerminalNotificationOSC9, TerminalNotificationOSC777:
+	default:
+		problems = append(problems, ValidationError{
+			Key: "terminal.notification",
+			Message: fmt.Sprintf(
+				"expected %q, %q or %q, got %q",
+				TerminalNotificationNone, TerminalNotificationOSC9, TerminalNotificationOSC777, c.Terminal.Notification,
+			),
+		})
+	}
+
+	if !i18n.IsSupported(c.Language) {
+		problems = append(problems, ValidationError{
+			Key:     "language",
+			Message: fmt.Sprintf("unsupported language %q, expected %s or one of %s", c.Language, i18n.Auto, strings.Join(i18n.Languages(), ", ")),
+		})
+	}
+
+	for i, notifier := range c.Notifiers {
+		problems = append(problems, validateNotifier(fmt.Sprintf("notifiers[%d]", i), notifier)...)
+	}
+
+	return problems
+}
+
+func validateTask(key string, task Task) []ValidationError {
+	var problems []ValidationError
+
+	if task.Duration <= 0 {
+		problems = append(problems, ValidationError{
+			Key:     key + ".duration",
+			Message: fmt.Sprintf("duration must be positive, got %v", task.Duration),
+		})
+	}
+
+	for i, command := range task.Then {
+		if len(command) == 0 || strings.TrimSpace(command[0]) == "" {
+			problems = append(problems, ValidationError{
+				Key:     fmt.Sprintf("%s.then[%d]", key, i),
+				Message: "command must not be empty",
+			})
+		}
+	}
+
+	for i, notifier := range task.Notifiers {
+		problems = append(problems, validateNotifier(fmt.Sprintf("%s.notifiers[%d]", key, i), notifier)...)
+	}
+
+	return problems
+}
+
+func validateNotifier(key string, notifier Notifier) []ValidationError {
+	var problems []ValidationError
+
+	switch notifier.Type {
+	case NotifierDesktop, NotifierTerminal, NotifierBell:
+	case NotifierSocket, NotifierFile:
+		if notifier.Path == "" {
+			problems = append(problems, ValidationError{
+				Key:     key + ".path",
+				Message: fmt.Sprintf("%s notifiers need a path", notifier.Type),
+			})
+		}
+	case NotifierNtfy, NotifierWebhook:
+		if u, err := url.Parse(notifier.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, ValidationError{
+				Key:     key + ".url",
+				Message: fmt.Sprintf("expected an http(s) URL, got %q", notifier.URL),
+			})
+		}
+	case NotifierCommand:
+		if len(notifier.Command) == 0 || strings.TrimSpace(notifier.Command[0]) == "" {
+			problems = append(problems, ValidationError{
+				Key:     key + ".command",
+				Message: "command must not be empty",
+			})
+		}
+	default:
+		// notifiers registered by other packages have no fields to check
+		if isNotifierType(notifier.Type) {
+			break
+		}
+
+		problems = append(problems, ValidationError{
+			Key: key + ".type",
+			Message: fmt.Sprintf(
+				"expected one of %s, got %q",
+				strings.Join(registeredNotifierTypes(), ", "), notifier.Type,
+			),
+		})
+	}
+
+	return problems
+}
+
+func validateStreak(key string, streak Streak) []ValidationError {
+	var problems []ValidationError
+
+	restDays := make(map[time.Weekday]bool)
+	for i, name := range streak.RestDays {
+		weekday, err := ParseWeekday(name)
+		if err != nil {
+			problems = append(problems, ValidationError{
+				Key:     fmt.Sprintf("%s.restDays[%d]", key, i),
+				Message: err.Error(),
+			})
+		}
+		restDays[weekday] = true
+	}
+
+	if len(restDays) == 7 {
+		problems = append(problems, ValidationError{
+			Key:     key + ".restDays",
+			Message: "at least one day must not be a rest day",
+		})
+	}
+
+	if streak.FreezesPerMonth < 0 {
+		problems = append(problems, ValidationError{
+			Key:     key + ".freezesPerMonth",
+			Message: fmt.Sprintf("must not be negative, got %d", streak.FreezesPerMonth),
+		})
+	}
+
+	if streak.MinDuration < 0 {
+		problems = append(problems, ValidationError{
+			Key:     key + ".minDuration",
+			Message: fmt.Sprintf("must not be negative, got %v", streak.MinDuration),
+		})
+	}
+
+	if streak.WeeklyMinDays < 1 || streak.WeeklyMinDays > 7 {
+		problems = append(problems, ValidationError{
+			Key:     key + ".weeklyMinDays",
+			Message: fmt.Sprintf("must be between 1 and 7, got %d", streak.WeeklyMinDays),
+		})
+	}
+
+	return problems
+}
+
+// validates the config file against the schema and the effective config against
+// the semantic rules, locating each problem in the config file when possible
+func validate(file string, c Config) []ValidationError {
+	var problems []ValidationError
+	var content []byte
+
+	if file != "" {
+		var err error
+		if content, err = os.ReadFile(file); err == nil {
+			problems, err = ValidateYAML(content)
+		}
+
+		if err != nil {
+			problems = append(problems, ValidationError{Message: err.Error()})
+		}
+	}
+
+	// semantic problems for keys already reported by the schema are duplicates
+	reported := make(map[string]bool)
+	for _, problem := range problems {
+		reported[strings.ToLower(problem.Key)] = true
+	}
+
+	for _, problem := range c.Validate() {
+		if !reported[strings.ToLower(problem.Key)] {
+			problems = append(problems, problem)
+		}
+	}
+
+	var document yaml.Node
+	if len(content) > 0 {
+		_ = yaml.Unmarshal(content, &document)
+	}
+
+	for i := range problems {
+		problems[i].File = file
+
+		if problems[i].Line > 0 || len(document.Content) == 0 {
+			continue
+		}
+
+		// values of the active profile take precedence over the base config
+		keys := []string{problems[i].Key}
+		if c.Profile != "" {
+			keys = append([]string{"profiles." + c.Profile + "." + problems[i].Key}, keys...)
+		}
+
+		for _, key := range keys {
+			if node := findKey(document.Content[0], key); node != nil {
+				problems[i].Line, problems[i].Column = node.Line, node.Column
+				break
+			}
+		}
+	}
+
+	return problems
+}
+
+// finds the value node for a key such as "work.then[0]"
+func findKey(node *yaml.Node, key string) *yaml.Node {
+	for part := range strings.SplitSeq(key, ".") {
+		name, index := part, -1
+
+		if open := strings.Index(part, "["); open >= 0 && strings.HasSuffix(part, "]") {
+			name = part[:open]
+			if i, err := strconv.Atoi(part[open+1 : len(part)-1]); err == nil {
+				index = i
+			}
+		}
+
+		if node = findMappingValue(node, name); node == nil {
+			return nil
+		}
+
+		if index >= 0 {
+			if node.Kind != yaml.SequenceNode || index >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[index]
+		}
+	}
+
+	return node
+}
+
+func findMappingValue(node *yaml.Node, name string) *yaml.Node {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, name) {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
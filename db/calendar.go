@@ -0,0 +1,130 @@
+package db
+
+import (
+	"log"
+	"time"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+// Calendar maps moments in time to the days sessions are grouped by.
+type Calendar struct {
+	// Location is the time zone days are computed in, nil for the local time zone
+	Location *time.Location
+
+	// DayStartsAt is the wall-clock time a day starts at,
+	// moments before it belong to the previous day
+	DayStartsAt time.Duration
+
+	// how many days after monday weeks start, so the zero value starts them on monday
+	weekOffset int
+}
+
+// DayPart is the part of a session that falls on a single day.
+type DayPart struct {
+	Date     time.Time
+	Duration time.Duration
+}
+
+// NewCalendar returns the calendar configured by the stats settings.
+func NewCalendar(stats config.Stats) Calendar {
+	location, err := stats.Location()
+	if err != nil {
+		log.Println("failed to load stats time zone, using local time:", err)
+		location = time.Local
+	}
+
+	firstWeekday, err := stats.WeekStart()
+	if err != nil {
+		log.Println("failed to parse the first day of the week, using monday:", err)
+		firstWeekday = time.Monday
+	}
+
+	return Calendar{Location: location, DayStartsAt: stats.DayStartsAt}.WithFirstWeekday(firstWeekday)
+}
+
+// WithFirstWeekday returns a copy of the calendar with weeks starting on weekday.
+func (c Calendar) WithFirstWeekday(weekday time.Weekday) Calendar {
+	c.weekOffset = (int(weekday) - int(time.Monday) + 7) % 7
+	return c
+}
+
+// FirstWeekday returns the day weeks start on.
+func (c Calendar) FirstWeekday() time.Weekday {
+	return time.Weekday((int(time.Monday) + c.weekOffset) % 7)
+}
+
+// Date returns the day containing t, at midnight in the calendar's time zone.
+func (c Calendar) Date(t time.Time) time.Time {
+	t = t.In(c.location())
+	year, month, day := t.Date()
+
+	// compare wall-clock times so DST changes don't move the day boundary
+	if sinceMidnight(t) < c.DayStartsAt {
+		day--
+	}
+
+	return time.Date(year, month, day, 0, 0, 0, 0, c.location())
+}
+
+// Start returns the moment the day of date begins.
+func (c Calendar) Start(date time.Time) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, int(c.DayStartsAt), c.location())
+}
+
+// Split divides the span starting at start and lasting duration
+// into the parts that fall on each day.
+func (c Calendar) Split(start time.Time, duration time.Duration) []DayPart {
+	var parts []DayPart
+	end := start.Add(duration)
+
+	for date := c.Date(start); start.Before(end); date = date.AddDate(0, 0, 1) {
+		// days are 23 or 25 hours long when DST changes
+		next := c.Start(date.AddDate(0, 0, 1))
+		if next.After(end) {
+			next = end
+		}
+
+		parts = append(parts, DayPart{Date: date, Duration: next.Sub(start)})
+		start = next
+	}
+
+	return parts
+}
+
+// WeekStart returns the first day of the week containing date.
+func (c Calendar) WeekStart(date time.Time) time.Time {
+	daysSinceStart := (int(date.Weekday()) - int(c.FirstWeekday()) + 7) % 7
+	return date.AddDate(0, 0, -daysSinceStart)
+}
+
+// Week returns the year and number of the week containing date.
+// Weeks starting on monday are ISO 8601 weeks, other weeks
+// take the number of the ISO week their middle day falls in.
+func (c Calendar) Week(date time.Time) (year, week int) {
+	return c.WeekStart(date).AddDate(0, 0, 3).ISOWeek()
+}
+
+// Today returns the current day.
+func (c Calendar) Today() time.Time {
+	return c.Date(time.Now())
+}
+
+// returns the wall-clock time of t
+func sinceMidnight(t time.Time) time.Duration {
+	hour, minute, second := t.Clock()
+
+	return time.Duration(hour)*time.Hour +
+		time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second +
+		time.Duration(t.Nanosecond())
+}
+
+func (c Calendar) location() *time.Location {
+	if c.Location == nil {
+		return time.Local
+	}
+
+	return c.Location
+}
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	// Europe/Berlin for the DST tests
+	_ "time/tzdata"
+)
+
+func TestCalendarDate(t *testing.T) {
+	location := time.FixedZone("UTC+2", 2*60*60)
+
+	testCases := []struct {
+		name        string
+		dayStartsAt time.Duration
+		moment      time.Time
+		want        string
+	}{
+		{"midnight boundary", 0, time.Date(2026, 3, 1, 0, 30, 0, 0, location), "2026-03-01"},
+		{"before day start", 4 * time.Hour, time.Date(2026, 3, 1, 3, 59, 0, 0, location), "2026-02-28"},
+		{"at day start", 4 * time.Hour, time.Date(2026, 3, 1, 4, 0, 0, 0, location), "2026-03-01"},
+		{"late evening", 4 * time.Hour, time.Date(2026, 3, 1, 23, 0, 0, 0, location), "2026-03-01"},
+		{"converted to calendar zone", 4 * time.Hour, time.Date(2026, 3, 1, 1, 0, 0, 0, time.UTC), "2026-02-28"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := Calendar{Location: location, DayStartsAt: tt.dayStartsAt}
+
+			if got := calendar.Date(tt.moment).Format(DateFormat); got != tt.want {
+				t.Fatalf("Date(%v) = %s, want %s", tt.moment, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarStart(t *testing.T) {
+	calendar := Calendar{Location: time.UTC, DayStartsAt: 4 * time.Hour}
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if got, want := calendar.Start(date), time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("Start(%v) = %v, want %v", date, got, want)
+	}
+}
+
+func TestCalendarWeek(t *testing.T) {
+	testCases := []struct {
+		name         string
+		firstWeekday time.Weekday
+		date         time.Time
+		wantStart    string
+		wantYear     int
+		wantWeek     int
+	}{
+		{"monday", time.Monday, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), "2026-02-23", 2026, 9},
+		{"sunday", time.Sunday, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), "2026-03-01", 2026, 10},
+		{"saturday", time.Saturday, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), "2026-02-28", 2026, 10},
+		{"iso week of the previous year", time.Monday, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), "2026-12-28", 2026, 53},
+		{"sunday week of the next year", time.Sunday, time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC), "2025-12-28", 2026, 1},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := Calendar{Location: time.UTC}.WithFirstWeekday(tt.firstWeekday)
+
+			if got := calendar.WeekStart(tt.date).Format(DateFormat); got != tt.wantStart {
+				t.Fatalf("WeekStart(%v) = %s, want %s", tt.date, got, tt.wantStart)
+			}
+
+			if year, week := calendar.Week(tt.date); year != tt.wantYear || week != tt.wantWeek {
+				t.Fatalf("Week(%v) = %d-W%02d, want %d-W%02d", tt.date, year, week, tt.wantYear, tt.wantWeek)
+			}
+		})
+	}
+}
+
+func TestCalendarSplit(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+
+	type part struct {
+		date     string
+		duration time.Duration
+	}
+
+	testCases := []struct {
+		name        string
+		dayStartsAt time.Duration
+		start       time.Time
+		duration    time.Duration
+		want        []part
+	}{
+		{
+			name:     "within a day",
+			start:    time.Date(2026, 1, 10, 9, 0, 0, 0, berlin),
+			duration: 25 * time.Minute,
+			want:     []part{{"2026-01-10", 25 * time.Minute}},
+		},
+		{
+			name:     "across midnight",
+			start:    time.Date(2026, 1, 10, 23, 40, 0, 0, berlin),
+			duration: 50 * time.Minute,
+			want:     []part{{"2026-01-10", 20 * time.Minute}, {"2026-01-11", 30 * time.Minute}},
+		},
+		{
+			name:        "across a later day start",
+			dayStartsAt: 4 * time.Hour,
+			start:       time.Date(2026, 1, 11, 3, 30, 0, 0, berlin),
+			duration:    time.Hour,
+			want:        []part{{"2026-01-10", 30 * time.Minute}, {"2026-01-11", 30 * time.Minute}},
+		},
+		{
+			// clocks skip from 02:00 to 03:00, the day is 23 hours long
+			name:     "spring forward",
+			start:    time.Date(2026, 3, 29, 0, 0, 0, 0, berlin),
+			duration: 24 * time.Hour,
+			want:     []part{{"2026-03-29", 23 * time.Hour}, {"2026-03-30", time.Hour}},
+		},
+		{
+			// clocks go back from 03:00 to 02:00, the day is 25 hours long
+			name:     "fall back",
+			start:    time.Date(2026, 10, 25, 0, 0, 0, 0, berlin),
+			duration: 25 * time.Hour,
+			want:     []part{{"2026-10-25", 25 * time.Hour}},
+		},
+		{
+			name:        "fall back with a later day start",
+			dayStartsAt: 4 * time.Hour,
+			start:       time.Date(2026, 10, 24, 23, 30, 0, 0, berlin),
+			duration:    6 * time.Hour,
+			want:        []part{{"2026-10-24", 5*time.Hour + 30*time.Minute}, {"2026-10-25", 30 * time.Minute}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := Calendar{Location: berlin, DayStartsAt: tt.dayStartsAt}
+
+			var got []part
+			for _, p := range calendar.Split(tt.start, tt.duration) {
+				got = append(got, part{p.Date.Format(DateFormat), p.Duration})
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Split = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Split = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -2,14 +2,13 @@
 package db
 
 import (
-	"errors"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
-	"runtime"
-	"strings"
 
 	"github.com/Bahaaio/pomo/config"
+	"github.com/Bahaaio/pomo/paths"
 	"github.com/jmoiron/sqlx"
 	_ "modernc.org/sqlite"
 )
@@ -19,22 +18,67 @@ const DBFile = config.AppName + ".db"
 // Connect connects to the SQLite database,
 // creates the necessary directories,
 // and performs migrations if needed.
+//
+// Use [Prepare] and [Open] to connect without migrating.
 func Connect() (*sqlx.DB, error) {
-	dbDir, err := getDBDir()
+	dbPath, err := Prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := Open(dbPath)
 	if err != nil {
-		log.Println("failed to get db path:", err)
 		return nil, err
 	}
 
+	// migrate the database
+	if _, err = Migrate(db, dbPath); err != nil {
+		log.Println("failed to migrate the db:", err)
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Prepare returns the database path after moving a legacy database into place
+// and creating its directory.
+func Prepare() (string, error) {
+	dbPath, err := Path()
+	if err != nil {
+		log.Println("failed to get db path:", err)
+		return "", err
+	}
+
+	// only the default location is migrated, an explicit path is used as is
+	if config.C.DB == "" {
+		if err = migrateLegacyDB(dbPath); err != nil {
+			log.Println("failed to migrate the db from its legacy location:", err)
+			return "", err
+		}
+	}
+
 	// create the db directory if it doesn't exist
-	if err = os.MkdirAll(dbDir, 0o755); err != nil {
+	if err = os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
 		log.Println("failed to create db directory:", err)
-		return nil, err
+		return "", err
 	}
 
-	dbPath := filepath.Join(dbDir, DBFile)
+	return dbPath, nil
+}
 
-	db, err := sqlx.Open("sqlite", dbPath)
+// Open opens the SQLite database at dbPath without migrating it.
+func Open(dbPath string) (*sqlx.DB, error) {
+	return open(dbPath)
+}
+
+// OpenReadOnly opens the existing SQLite database at dbPath,
+// it is neither created nor written to.
+func OpenReadOnly(dbPath string) (*sqlx.DB, error) {
+	return open("file:" + dbPath + "?mode=ro")
+}
+
+func open(dataSourceName string) (*sqlx.DB, error) {
+	db, err := sqlx.Open("sqlite", dataSourceName)
 	if err != nil {
 		log.Println("failed to connect to the db:", err)
 		return nil, err
@@ -50,82 +94,77 @@ func Connect() (*sqlx.DB, error) {
 	// limit the number of open connections to 1
 	db.SetMaxOpenConns(1)
 
-	// migrate the database
-	if err = createSchema(db); err != nil {
-		log.Println("failed to migrate the db:", err)
-		return nil, err
-	}
-
 	return db, nil
 }
 
-func createSchema(db *sqlx.DB) error {
-	if _, err := db.Exec(schema); err != nil {
-		return err
+// Path returns the path of the database file,
+// either the configured one or the default location in the data directory.
+func Path() (string, error) {
+	if config.C.DB != "" {
+		return config.C.DB, nil
 	}
-	log.Println("created the schema")
 
-	// migration: add source column for distinguishing screen vs manual durations
-	if !tableHasColumn(db, "sessions", "source") {
-		if _, err := db.Exec(`ALTER TABLE sessions ADD COLUMN source TEXT NOT NULL DEFAULT 'screen';`); err != nil {
-			return err
-		}
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		return "", err
 	}
 
-	return nil
+	return filepath.Join(dataDir, DBFile), nil
 }
 
-func tableHasColumn(db *sqlx.DB, tableName, columnName string) bool {
-	query := "PRAGMA table_info(" + tableName + ");"
-	rows, err := db.Queryx(query)
+// moves the database from the location used before XDG directories were honored
+// to dbPath, unless there already is a database at dbPath
+func migrateLegacyDB(dbPath string) error {
+	legacyDir, err := paths.LegacyDataDir()
 	if err != nil {
-		return false
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var (
-			cid      int
-			name     string
-			colType  string
-			notNull  int
-			defaultV *string
-			primaryK int
-		)
-
-		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultV, &primaryK); err != nil {
-			return false
-		}
+		return nil // nothing to migrate
+	}
 
-		if strings.EqualFold(name, columnName) {
-			return true
-		}
+	legacyPath := filepath.Join(legacyDir, DBFile)
+	if legacyPath == dbPath || fileExists(dbPath) || !fileExists(legacyPath) {
+		return nil
 	}
 
-	return false
+	log.Printf("moving db from %s to %s", legacyPath, dbPath)
+
+	if err = os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
+		return err
+	}
+
+	// rename fails across file systems, fall back to copying
+	if err = os.Rename(legacyPath, dbPath); err == nil {
+		return nil
+	}
+
+	if err = copyFile(legacyPath, dbPath); err != nil {
+		return err
+	}
+
+	return os.Remove(legacyPath)
 }
 
-// returns the path to the db directory
-func getDBDir() (string, error) {
-	var dir string
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
 
-	// on Linux and macOS, use ~/.local/state
-	if runtime.GOOS == "linux" || runtime.GOOS == "darwin" {
-		dir = os.Getenv("HOME")
-		if dir == "" {
-			return "", errors.New("$HOME is not defined")
-		}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
 
-		dir = filepath.Join(dir, ".local", "state")
-	} else {
-		// on other OSes, use the standard user config directory
-		var err error
-		dir, err = os.UserConfigDir()
-		if err != nil {
-			return "", err
-		}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
 	}
 
-	// join the dir with the app name
-	return filepath.Join(dir, config.AppName), nil
+	return out.Close()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
 }
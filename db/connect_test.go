@@ -0,0 +1,80 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestMigrateLegacyDB(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("legacy db location only differs on Linux and macOS")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+
+	legacyPath := filepath.Join(home, ".local", "state", "pomo", DBFile)
+	if err := os.MkdirAll(filepath.Dir(legacyPath), 0o755); err != nil {
+		t.Fatalf("create legacy dir: %v", err)
+	}
+	if err := os.WriteFile(legacyPath, []byte("sessions"), 0o644); err != nil {
+		t.Fatalf("write legacy db: %v", err)
+	}
+
+	dbPath, err := Path()
+	if err != nil {
+		t.Fatalf("get db path: %v", err)
+	}
+	if want := filepath.Join(home, ".local", "share", "pomo", DBFile); dbPath != want {
+		t.Fatalf("db path = %q, want %q", dbPath, want)
+	}
+
+	if err := migrateLegacyDB(dbPath); err != nil {
+		t.Fatalf("migrate legacy db: %v", err)
+	}
+
+	content, err := os.ReadFile(dbPath)
+	if err != nil || string(content) != "sessions" {
+		t.Fatalf("migrated db content = %q (%v)", content, err)
+	}
+	if fileExists(legacyPath) {
+		t.Fatalf("legacy db should have been moved")
+	}
+}
+
+func TestMigrateLegacyDB_KeepsExistingDB(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("legacy db location only differs on Linux and macOS")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+
+	legacyPath := filepath.Join(home, ".local", "state", "pomo", DBFile)
+	dbPath := filepath.Join(home, "data", "pomo", DBFile)
+
+	for path, content := range map[string]string{legacyPath: "old", dbPath: "new"} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write db: %v", err)
+		}
+	}
+
+	if err := migrateLegacyDB(dbPath); err != nil {
+		t.Fatalf("migrate legacy db: %v", err)
+	}
+
+	content, _ := os.ReadFile(dbPath)
+	if string(content) != "new" {
+		t.Fatalf("existing db was overwritten: %q", content)
+	}
+	if !fileExists(legacyPath) {
+		t.Fatalf("legacy db should be left alone when a db already exists")
+	}
+}
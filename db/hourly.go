@@ -0,0 +1,67 @@
+package db
+
+import "time"
+
+// workSpan is the start and duration of a work session.
+type workSpan struct {
+	StartedAt time.Time
+	Duration  time.Duration
+}
+
+// calculateHourlyStats spreads the work sessions over the hours of the week they fall in,
+// using the wall-clock time of the calendar's time zone.
+//
+// Start times are averaged relative to the start of the day,
+// so sessions after midnight count as late rather than early when the day starts later.
+func calculateHourlyStats(spans []workSpan, calendar Calendar) HourlyStats {
+	var stats HourlyStats
+	var startSum time.Duration
+	var weekdayStartSums [7]time.Duration
+
+	for _, span := range spans {
+		start := span.StartedAt.In(calendar.location())
+		end := start.Add(span.Duration)
+
+		for current := start; current.Before(end); {
+			// the next full hour, truncating in the location keeps odd offsets like +05:30 right
+			next := time.Date(current.Year(), current.Month(), current.Day(), current.Hour()+1, 0, 0, 0, current.Location())
+			if next.After(end) {
+				next = end
+			}
+
+			stats.Work[current.Weekday()][current.Hour()] += next.Sub(current)
+			current = next
+		}
+
+		// wall-clock time since the day start, so DST changes don't shift it
+		sinceDayStart := sinceMidnight(start) - calendar.DayStartsAt
+		if sinceDayStart < 0 {
+			sinceDayStart += 24 * time.Hour
+		}
+
+		weekday := calendar.Date(start).Weekday()
+
+		startSum += sinceDayStart
+		weekdayStartSums[weekday] += sinceDayStart
+		stats.Sessions++
+		stats.WeekdaySessions[weekday]++
+	}
+
+	if stats.Sessions > 0 {
+		stats.AverageStart = averageStart(startSum, stats.Sessions, calendar)
+	}
+
+	for weekday, sessions := range stats.WeekdaySessions {
+		if sessions > 0 {
+			stats.WeekdayAverageStarts[weekday] = averageStart(weekdayStartSums[weekday], sessions, calendar)
+		}
+	}
+
+	return stats
+}
+
+// converts the sum of times since the day start to an average time of day
+func averageStart(sum time.Duration, count int, calendar Calendar) time.Duration {
+	average := sum/time.Duration(count) + calendar.DayStartsAt
+	return average % (24 * time.Hour)
+}
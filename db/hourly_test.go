@@ -0,0 +1,78 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateHourlyStats(t *testing.T) {
+	location := time.FixedZone("UTC+5:30", 5*60*60+30*60)
+
+	// friday 2026-02-13
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 2, day, hour, minute, 0, 0, location)
+	}
+
+	testCases := []struct {
+		name         string
+		dayStartsAt  time.Duration
+		spans        []workSpan
+		wantWork     map[[2]int]time.Duration // by weekday and hour
+		wantAverage  time.Duration
+		wantSessions [7]int
+	}{
+		{
+			name:         "within an hour",
+			spans:        []workSpan{{at(13, 9, 10), 25 * time.Minute}},
+			wantWork:     map[[2]int]time.Duration{{5, 9}: 25 * time.Minute},
+			wantAverage:  9*time.Hour + 10*time.Minute,
+			wantSessions: [7]int{5: 1},
+		},
+		{
+			name:  "across hours and midnight",
+			spans: []workSpan{{at(13, 23, 40), 50 * time.Minute}},
+			wantWork: map[[2]int]time.Duration{
+				{5, 23}: 20 * time.Minute,
+				{6, 0}:  30 * time.Minute,
+			},
+			wantAverage:  23*time.Hour + 40*time.Minute,
+			wantSessions: [7]int{5: 1},
+		},
+		{
+			name:        "late sessions average past midnight",
+			dayStartsAt: 4 * time.Hour,
+			spans: []workSpan{
+				{at(13, 23, 0), 10 * time.Minute},
+				{at(14, 1, 0), 10 * time.Minute},
+			},
+			wantWork: map[[2]int]time.Duration{
+				{5, 23}: 10 * time.Minute,
+				{6, 1}:  10 * time.Minute,
+			},
+			wantAverage:  0,
+			wantSessions: [7]int{5: 2},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateHourlyStats(tt.spans, Calendar{Location: location, DayStartsAt: tt.dayStartsAt})
+
+			for weekday := range 7 {
+				for hour := range 24 {
+					if want := tt.wantWork[[2]int{weekday, hour}]; got.Work[weekday][hour] != want {
+						t.Fatalf("work on %s at %d:00 = %v, want %v", time.Weekday(weekday), hour, got.Work[weekday][hour], want)
+					}
+				}
+			}
+
+			if got.AverageStart != tt.wantAverage {
+				t.Fatalf("average start = %v, want %v", got.AverageStart, tt.wantAverage)
+			}
+
+			if got.WeekdaySessions != tt.wantSessions {
+				t.Fatalf("weekday sessions = %v, want %v", got.WeekdaySessions, tt.wantSessions)
+			}
+		})
+	}
+}
@@ -0,0 +1,481 @@
+package db
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const migrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations(
+	version INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	applied_at TEXT NOT NULL
+);
+`
+
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sqlx.Tx) error
+}
+
+// migrations are applied in order, each in its own transaction.
+// Never edit an existing migration, always append a new one.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create sessions table",
+		up: execSQL(`
+			CREATE TABLE IF NOT EXISTS sessions(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				type TEXT NOT NULL,
+				duration INTEGER NOT NULL,
+				started_at TEXT NOT NULL
+			);
+		`),
+	},
+	{
+		// databases created before migrations were versioned may already have it
+		version: 2,
+		name:    "add session source",
+		up:      addColumnIfMissing("sessions", "source", "TEXT NOT NULL DEFAULT 'screen'"),
+	},
+	{
+		version: 3,
+		name:    "add session profile",
+		up:      addColumnIfMissing("sessions", "profile", "TEXT NOT NULL DEFAULT ''"),
+	},
+	{
+		version: 4,
+		name:    "store session times as utc epochs",
+		up:      storeSessionTimesAsEpochs,
+	},
+	{
+		// sessions recorded before have an unknown outcome and no planned duration
+		version: 5,
+		name:    "add session outcome and planned duration",
+		up: execSQL(`
+			ALTER TABLE sessions ADD COLUMN outcome TEXT NOT NULL DEFAULT '';
+			ALTER TABLE sessions ADD COLUMN planned_duration INTEGER NOT NULL DEFAULT 0;
+		`),
+	},
+	{
+		// a rating of 0 means unrated
+		version: 6,
+		name:    "add session note and rating",
+		up: execSQL(`
+			ALTER TABLE sessions ADD COLUMN note TEXT NOT NULL DEFAULT '';
+			ALTER TABLE sessions ADD COLUMN rating INTEGER NOT NULL DEFAULT 0;
+		`),
+	},
+	{
+		// sessions without a todo have a todo_id of 0
+		version: 7,
+		name:    "add todos",
+		up: execSQL(`
+			CREATE TABLE todos(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				title TEXT NOT NULL,
+				estimate INTEGER NOT NULL DEFAULT 0,
+				created_at INTEGER NOT NULL,
+				done_at INTEGER
+			);
+			ALTER TABLE sessions ADD COLUMN todo_id INTEGER NOT NULL DEFAULT 0;
+			CREATE INDEX idx_sessions_todo_id ON sessions(todo_id);
+		`),
+	},
+	{
+		version: 8,
+		name:    "add interruptions",
+		up: execSQL(`
+			CREATE TABLE interruptions(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				session_id INTEGER NOT NULL,
+				kind TEXT NOT NULL,
+				note TEXT NOT NULL DEFAULT '',
+				occurred_at INTEGER NOT NULL
+			);
+			CREATE INDEX idx_interruptions_session_id ON interruptions(session_id);
+			CREATE INDEX idx_interruptions_occurred_at ON interruptions(occurred_at);
+		`),
+	},
+	{
+		version: 9,
+		name:    "add pauses",
+		up: execSQL(`
+			CREATE TABLE pauses(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				session_id INTEGER NOT NULL,
+				started_at INTEGER NOT NULL,
+				ended_at INTEGER NOT NULL
+			);
+			CREATE INDEX idx_pauses_session_id ON pauses(session_id);
+		`),
+	},
+}
+
+// MigrationStatus describes a migration and whether it has been applied.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// MigrationResult describes the outcome of [Migrate].
+type MigrationResult struct {
+	Applied []MigrationStatus
+
+	// BackupPath is the backup taken before migrating, empty if none was taken
+	BackupPath string
+}
+
+// Migrate applies all pending migrations.
+// If dbPath is not empty, an existing database is backed up next to it before migrating.
+func Migrate(db *sqlx.DB, dbPath string) (MigrationResult, error) {
+	var result MigrationResult
+
+	if _, err := db.Exec(migrationsTable); err != nil {
+		return result, err
+	}
+
+	pending, err := pendingMigrations(db)
+	if err != nil || len(pending) == 0 {
+		return result, err
+	}
+
+	if dbPath != "" && tableExists(db, "sessions") {
+		if result.BackupPath, err = Backup(db, dbPath); err != nil {
+			return result, fmt.Errorf("failed to back up the db before migrating: %w", err)
+		}
+		log.Println("backed up the db to", result.BackupPath)
+	}
+
+	for _, m := range pending {
+		appliedAt, err := applyMigration(db, m)
+		if err != nil {
+			return result, fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		log.Printf("applied migration %d: %s", m.version, m.name)
+
+		result.Applied = append(result.Applied, MigrationStatus{Version: m.version, Name: m.name, AppliedAt: &appliedAt})
+	}
+
+	return result, nil
+}
+
+// GetMigrationStatus returns all known migrations and when they were applied,
+// it doesn't write to the database.
+func GetMigrationStatus(db *sqlx.DB) ([]MigrationStatus, error) {
+	appliedAt, err := getAppliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.version, Name: m.name}
+		if t, ok := appliedAt[m.version]; ok {
+			status.AppliedAt = &t
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// SchemaVersion returns the version of the latest known migration.
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// BackupsKept is the number of backups kept next to the database,
+// older ones are removed when a new backup is taken
+const BackupsKept = 3
+
+// Backup copies the database into a new file next to dbPath and returns its path.
+// Only the latest [BackupsKept] backups are kept.
+func Backup(db *sqlx.DB, dbPath string) (string, error) {
+	version, err := currentVersion(db)
+	if err != nil {
+		return "", err
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d-%s.bak", dbPath, version, time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(filepath.Dir(backupPath), 0o755); err != nil {
+		return "", err
+	}
+
+	// VACUUM INTO writes a consistent copy even while the db is open
+	if _, err := db.Exec("VACUUM INTO ?;", backupPath); err != nil {
+		return "", err
+	}
+
+	if err := pruneBackups(dbPath); err != nil {
+		log.Println("failed to remove old db backups:", err)
+	}
+
+	return backupPath, nil
+}
+
+// removes all but the latest BackupsKept backups of the database at dbPath
+func pruneBackups(dbPath string) error {
+	entries, err := os.ReadDir(filepath.Dir(dbPath))
+	if err != nil {
+		return err
+	}
+
+	type backup struct {
+		path    string
+		modTime time.Time
+	}
+
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filepath.Base(dbPath)+".v") || !strings.HasSuffix(name, ".bak") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		backups = append(backups, backup{filepath.Join(filepath.Dir(dbPath), name), info.ModTime()})
+	}
+
+	if len(backups) <= BackupsKept {
+		return nil
+	}
+
+	// newest first, names break ties as their timestamps only have seconds
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].modTime.Equal(backups[j].modTime) {
+			return backups[i].modTime.After(backups[j].modTime)
+		}
+		return backups[i].path > backups[j].path
+	})
+
+	for _, old := range backups[BackupsKept:] {
+		if err := os.Remove(old.path); err != nil {
+			return err
+		}
+		log.Println("removed old db backup", old.path)
+	}
+
+	return nil
+}
+
+func pendingMigrations(db *sqlx.DB) ([]migration, error) {
+	appliedAt, err := getAppliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []migration
+	for _, m := range migrations {
+		if _, ok := appliedAt[m.version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+
+	return pending, nil
+}
+
+func currentVersion(db *sqlx.DB) (int, error) {
+	if !tableExists(db, "schema_migrations") {
+		return 0, nil
+	}
+
+	var version int
+	err := db.Get(&version, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations;")
+	return version, err
+}
+
+// returns when the applied migrations were applied, none if the migrations table doesn't exist yet
+func getAppliedMigrations(db *sqlx.DB) (map[int]time.Time, error) {
+	if !tableExists(db, "schema_migrations") {
+		return map[int]time.Time{}, nil
+	}
+
+	var rows []struct {
+		Version   int    `db:"version"`
+		AppliedAt string `db:"applied_at"`
+	}
+
+	if err := db.Select(&rows, "SELECT version, applied_at FROM schema_migrations;"); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]time.Time, len(rows))
+	for _, row := range rows {
+		appliedAt, _ := time.Parse(time.RFC3339, row.AppliedAt)
+		applied[row.Version] = appliedAt
+	}
+
+	return applied, nil
+}
+
+func applyMigration(db *sqlx.DB, m migration) (time.Time, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := m.up(tx); err != nil {
+		return time.Time{}, err
+	}
+
+	appliedAt := time.Now().Truncate(time.Second)
+	if _, err := tx.Exec(
+		"INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?);",
+		m.version, m.name, appliedAt.Format(time.RFC3339),
+	); err != nil {
+		return time.Time{}, err
+	}
+
+	return appliedAt, tx.Commit()
+}
+
+// legacyTimeLayouts are the formats started_at was stored in before it became an epoch
+var legacyTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05",
+}
+
+// rebuilds the sessions table with started_at and ended_at as UTC unix seconds,
+// keeping the time zone each session was recorded in
+func storeSessionTimesAsEpochs(tx *sqlx.Tx) error {
+	if _, err := tx.Exec(`
+		CREATE TABLE sessions_new(
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			type TEXT NOT NULL,
+			duration INTEGER NOT NULL,
+			started_at INTEGER NOT NULL,
+			ended_at INTEGER NOT NULL,
+			tz_name TEXT NOT NULL DEFAULT '',
+			tz_offset INTEGER NOT NULL DEFAULT 0,
+			source TEXT NOT NULL DEFAULT 'screen',
+			profile TEXT NOT NULL DEFAULT ''
+		);
+	`); err != nil {
+		return err
+	}
+
+	var sessions []struct {
+		ID        int64  `db:"id"`
+		Type      string `db:"type"`
+		Duration  int64  `db:"duration"`
+		StartedAt string `db:"started_at"`
+		Source    string `db:"source"`
+		Profile   string `db:"profile"`
+	}
+
+	if err := tx.Select(&sessions, "SELECT id, type, duration, started_at, source, profile FROM sessions;"); err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		startedAt, err := parseLegacyTime(session.StartedAt)
+		if err != nil {
+			return fmt.Errorf("session %d: %w", session.ID, err)
+		}
+
+		zoneName, zoneOffset := startedAt.Zone()
+		endedAt := startedAt.Add(time.Duration(session.Duration))
+
+		if _, err := tx.Exec(
+			`
+			INSERT INTO sessions_new (id, type, duration, started_at, ended_at, tz_name, tz_offset, source, profile)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+			`,
+			session.ID, session.Type, session.Duration,
+			startedAt.Unix(), endedAt.Unix(), zoneName, zoneOffset,
+			session.Source, session.Profile,
+		); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(`
+		DROP TABLE sessions;
+		ALTER TABLE sessions_new RENAME TO sessions;
+		CREATE INDEX idx_sessions_started_at ON sessions(started_at);
+	`)
+	return err
+}
+
+func parseLegacyTime(value string) (time.Time, error) {
+	for _, layout := range legacyTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+}
+
+func execSQL(query string) func(tx *sqlx.Tx) error {
+	return func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(query)
+		return err
+	}
+}
+
+func addColumnIfMissing(table, column, definition string) func(tx *sqlx.Tx) error {
+	return func(tx *sqlx.Tx) error {
+		if tableHasColumn(tx, table, column) {
+			return nil
+		}
+
+		_, err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition + ";")
+		return err
+	}
+}
+
+func tableExists(db sqlx.Queryer, tableName string) bool {
+	var count int
+	err := sqlx.Get(db, &count, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?;", tableName)
+	return err == nil && count > 0
+}
+
+func tableHasColumn(db sqlx.Queryer, tableName, columnName string) bool {
+	query := "PRAGMA table_info(" + tableName + ");"
+	rows, err := db.Queryx(query)
+	if err != nil {
+		return false
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid      int
+			name     string
+			colType  string
+			notNull  int
+			defaultV *string
+			primaryK int
+		)
+
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultV, &primaryK); err != nil {
+			return false
+		}
+
+		if strings.EqualFold(name, columnName) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,318 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// schemas of databases created before migrations were versioned
+var historicalSchemas = map[string]string{
+	"empty": ``,
+	"without source": `
+		CREATE TABLE sessions(
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			type TEXT NOT NULL,
+			duration INTEGER NOT NULL,
+			started_at TEXT NOT NULL
+		);
+		INSERT INTO sessions (type, duration, started_at) VALUES ('work', 1500000000000, '2026-02-13T09:00:00+02:00');
+	`,
+	"with source": `
+		CREATE TABLE sessions(
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			type TEXT NOT NULL,
+			duration INTEGER NOT NULL,
+			started_at TEXT NOT NULL,
+			source TEXT NOT NULL DEFAULT 'screen'
+		);
+		INSERT INTO sessions (type, duration, started_at, source) VALUES ('work', 1500000000000, '2026-02-13T09:00:00+02:00', 'other');
+	`,
+	"with profile": `
+		CREATE TABLE sessions(
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			type TEXT NOT NULL,
+			duration INTEGER NOT NULL,
+			started_at TEXT NOT NULL,
+			source TEXT NOT NULL DEFAULT 'screen',
+			profile TEXT NOT NULL DEFAULT ''
+		);
+		INSERT INTO sessions (type, duration, started_at, source, profile) VALUES ('work', 1500000000000, '2026-02-13T09:00:00+02:00', 'screen', 'deep');
+	`,
+}
+
+func openTestDB(t *testing.T, path string) *sqlx.DB {
+	t.Helper()
+
+	database, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	database.SetMaxOpenConns(1)
+	t.Cleanup(func() { database.Close() })
+
+	return database
+}
+
+func TestMigrate_FromHistoricalSchemas(t *testing.T) {
+	for name, historical := range historicalSchemas {
+		t.Run(name, func(t *testing.T) {
+			database := openTestDB(t, ":memory:")
+
+			if _, err := database.Exec(historical); err != nil {
+				t.Fatalf("create historical schema: %v", err)
+			}
+
+			if _, err := Migrate(database, ""); err != nil {
+				t.Fatalf("migrate: %v", err)
+			}
+
+			assertLatestSchema(t, database)
+
+			// existing sessions are kept
+			repo := NewSessionRepo(database)
+			stats, err := repo.GetAllTimeStats()
+			if err != nil {
+				t.Fatalf("get all-time stats: %v", err)
+			}
+			want := 1
+			if historical == "" {
+				want = 0
+			}
+			if stats.TotalSessions != want {
+				t.Fatalf("total sessions = %d, want %d", stats.TotalSessions, want)
+			}
+		})
+	}
+}
+
+func TestMigrate_ConvertsStartTimesToEpochs(t *testing.T) {
+	database := openTestDB(t, ":memory:")
+
+	if _, err := database.Exec(historicalSchemas["with profile"]); err != nil {
+		t.Fatalf("create historical schema: %v", err)
+	}
+
+	if _, err := Migrate(database, ""); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	var session struct {
+		StartedAt int64  `db:"started_at"`
+		EndedAt   int64  `db:"ended_at"`
+		TZOffset  int    `db:"tz_offset"`
+		Profile   string `db:"profile"`
+	}
+	if err := database.Get(&session, "SELECT started_at, ended_at, tz_offset, profile FROM sessions;"); err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+
+	// 2026-02-13T09:00:00+02:00 lasting 25 minutes
+	startedAt := time.Date(2026, 2, 13, 7, 0, 0, 0, time.UTC).Unix()
+	if session.StartedAt != startedAt || session.EndedAt != startedAt+25*60 {
+		t.Fatalf("times = %d..%d, want %d..%d", session.StartedAt, session.EndedAt, startedAt, startedAt+25*60)
+	}
+	if session.TZOffset != 2*60*60 || session.Profile != "deep" {
+		t.Fatalf("session = %+v", session)
+	}
+}
+
+func TestMigrate_FromEveryVersion(t *testing.T) {
+	for from := range migrations {
+		database := openTestDB(t, ":memory:")
+
+		// apply the first migrations like an older pomo version would have
+		if _, err := database.Exec(migrationsTable); err != nil {
+			t.Fatalf("create migrations table: %v", err)
+		}
+		for _, m := range migrations[:from] {
+			if _, err := applyMigration(database, m); err != nil {
+				t.Fatalf("apply migration %d: %v", m.version, err)
+			}
+		}
+
+		result, err := Migrate(database, "")
+		if err != nil {
+			t.Fatalf("migrate from version %d: %v", from, err)
+		}
+		if len(result.Applied) != len(migrations)-from {
+			t.Fatalf("applied %d migrations from version %d, want %d", len(result.Applied), from, len(migrations)-from)
+		}
+
+		assertLatestSchema(t, database)
+	}
+}
+
+func TestMigrate_IsIdempotent(t *testing.T) {
+	database := openTestDB(t, ":memory:")
+
+	if _, err := Migrate(database, ""); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	result, err := Migrate(database, "")
+	if err != nil {
+		t.Fatalf("migrate again: %v", err)
+	}
+	if len(result.Applied) != 0 || result.BackupPath != "" {
+		t.Fatalf("expected no pending migrations, got %+v", result)
+	}
+}
+
+func TestMigrate_BacksUpExistingDB(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), DBFile)
+	database := openTestDB(t, dbPath)
+
+	if _, err := database.Exec(historicalSchemas["with source"]); err != nil {
+		t.Fatalf("create historical schema: %v", err)
+	}
+
+	result, err := Migrate(database, dbPath)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	backups, err := filepath.Glob(dbPath + ".v0-*.bak")
+	if err != nil || len(backups) != 1 || backups[0] != result.BackupPath {
+		t.Fatalf("expected one backup at %q, got %v (%v)", result.BackupPath, backups, err)
+	}
+
+	// the backup has the old schema and data
+	backup := openTestDB(t, backups[0])
+	if tableHasColumn(backup, "sessions", "profile") {
+		t.Fatalf("backup should have the schema from before migrating")
+	}
+
+	var count int
+	if err := backup.Get(&count, "SELECT COUNT(*) FROM sessions;"); err != nil || count != 1 {
+		t.Fatalf("backup sessions = %d (%v), want 1", count, err)
+	}
+}
+
+func TestGetMigrationStatus(t *testing.T) {
+	database := openTestDB(t, ":memory:")
+
+	if _, err := database.Exec(migrationsTable); err != nil {
+		t.Fatalf("create migrations table: %v", err)
+	}
+	if _, err := applyMigration(database, migrations[0]); err != nil {
+		t.Fatalf("apply first migration: %v", err)
+	}
+
+	statuses, err := GetMigrationStatus(database)
+	if err != nil {
+		t.Fatalf("get migration status: %v", err)
+	}
+	if len(statuses) != len(migrations) {
+		t.Fatalf("got %d statuses, want %d", len(statuses), len(migrations))
+	}
+
+	if statuses[0].AppliedAt == nil {
+		t.Fatalf("first migration should be applied")
+	}
+	for _, status := range statuses[1:] {
+		if status.AppliedAt != nil {
+			t.Fatalf("migration %d should be pending", status.Version)
+		}
+	}
+}
+
+func TestGetMigrationStatus_DoesNotWrite(t *testing.T) {
+	database := openTestDB(t, ":memory:")
+
+	statuses, err := GetMigrationStatus(database)
+	if err != nil {
+		t.Fatalf("get migration status: %v", err)
+	}
+
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			t.Fatalf("migration %d should be pending", status.Version)
+		}
+	}
+
+	if tableExists(database, "schema_migrations") {
+		t.Fatalf("getting the status should not create the migrations table")
+	}
+}
+
+func TestBackup_KeepsLatestBackups(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), DBFile)
+	database := openTestDB(t, dbPath)
+
+	// backups of another database in the same directory are kept
+	other := filepath.Join(filepath.Dir(dbPath), "other.db.v0-20260101-000000.bak")
+	if err := os.WriteFile(other, nil, 0o644); err != nil {
+		t.Fatalf("write other backup: %v", err)
+	}
+
+	start := time.Date(2026, 2, 13, 9, 0, 0, 0, time.UTC)
+
+	var older []string
+	for i := range BackupsKept + 2 {
+		// stand-in for older backups, distinct names and modification times
+		path := fmt.Sprintf("%s.v%d-2026021%d-090000.bak", dbPath, i, i)
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatalf("write backup: %v", err)
+		}
+		if err := os.Chtimes(path, start, start.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("set backup time: %v", err)
+		}
+
+		older = append(older, path)
+	}
+
+	backupPath, err := Backup(database, dbPath)
+	if err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	latest := append(older[len(older)-(BackupsKept-1):], backupPath)
+
+	backups, err := filepath.Glob(dbPath + ".v*.bak")
+	if err != nil {
+		t.Fatalf("glob backups: %v", err)
+	}
+
+	sort.Strings(backups)
+	sort.Strings(latest)
+	if !slices.Equal(backups, latest) {
+		t.Fatalf("backups = %v, want %v", backups, latest)
+	}
+
+	if _, err := os.Stat(other); err != nil {
+		t.Fatalf("backup of another database was removed: %v", err)
+	}
+}
+
+func assertLatestSchema(t *testing.T, database *sqlx.DB) {
+	t.Helper()
+
+	for _, column := range []string{
+		"id", "type", "duration", "started_at", "ended_at", "tz_name", "tz_offset", "source", "profile",
+		"outcome", "planned_duration", "note", "rating", "todo_id",
+	} {
+		if !tableHasColumn(database, "sessions", column) {
+			t.Fatalf("sessions table is missing column %q", column)
+		}
+	}
+
+	for _, table := range []string{"todos", "interruptions", "pauses"} {
+		if !tableExists(database, table) {
+			t.Fatalf("%s table is missing", table)
+		}
+	}
+
+	version, err := currentVersion(database)
+	if err != nil {
+		t.Fatalf("get current version: %v", err)
+	}
+	if version != SchemaVersion() {
+		t.Fatalf("schema version = %d, want %d", version, SchemaVersion())
+	}
+}
@@ -6,22 +6,63 @@ import (
 	"github.com/Bahaaio/pomo/config"
 )
 
-var schema = `
-CREATE TABLE IF NOT EXISTS sessions(
-	id INTEGER PRIMARY KEY AUTOINCREMENT,
-	type TEXT NOT NULL,
-	duration INTEGER NOT NULL,
-	started_at TEXT NOT NULL,
-	source TEXT NOT NULL DEFAULT 'screen'
-);
-`
-
 type Session struct {
-	ID        int           `db:"id"`
-	Type      string        `db:"type"`
-	Duration  time.Duration `db:"duration"`
-	StartedAt time.Time     `db:"started_at"`
-	Source    string        `db:"source"`
+	ID              int           `db:"id"`
+	Type            string        `db:"type"`
+	Duration        time.Duration `db:"duration"`
+	PlannedDuration time.Duration `db:"planned_duration"`
+	StartedAt       time.Time     `db:"started_at"`
+	Source          string        `db:"source"`
+	Profile         string        `db:"profile"`
+	Outcome         string        `db:"outcome"`
+
+	// Note and Rating are written after a work session, Rating is 1-5 or 0 if unrated
+	Note   string `db:"note"`
+	Rating int    `db:"rating"`
+
+	// TodoID is the todo the session was spent on, 0 if none
+	TodoID int64 `db:"todo_id"`
+}
+
+// Todo is an item of the task list sessions can be spent on.
+type Todo struct {
+	ID    int64
+	Title string
+
+	// Estimate is the number of pomodoros the todo is expected to take, 0 if not estimated
+	Estimate int
+
+	// Pomodoros is the number of finished work sessions spent on the todo
+	Pomodoros    int
+	WorkDuration time.Duration
+
+	CreatedAt time.Time
+	DoneAt    *time.Time
+}
+
+// Done reports whether the todo was marked as done.
+func (t Todo) Done() bool {
+	return t.DoneAt != nil
+}
+
+// Interruption is logged while a session is running, without stopping it.
+type Interruption struct {
+	ID         int64
+	SessionID  int64
+	Kind       InterruptionKind
+	Note       string
+	OccurredAt time.Time
+}
+
+// Pause is an interval a session was paused for.
+type Pause struct {
+	SessionID          int64
+	StartedAt, EndedAt time.Time
+}
+
+// Duration returns how long the pause lasted.
+func (p Pause) Duration() time.Duration {
+	return p.EndedAt.Sub(p.StartedAt)
 }
 
 type AllTimeStats struct {
@@ -30,20 +71,116 @@ type AllTimeStats struct {
 	TotalBreakDuration time.Duration `db:"total_break_duration"`
 }
 
+type ProfileStats struct {
+	Profile string `db:"profile"`
+	AllTimeStats
+}
+
 type DailyStat struct {
-	Date               string        `db:"day"`
-	ScreenWorkDuration time.Duration `db:"screen_work_duration"`
-	OtherWorkDuration  time.Duration `db:"other_work_duration"`
-	WorkDuration       time.Duration `db:"work_duration"`
+	Date string
+
+	// Label replaces the weekday in charts when a stat covers more than a day
+	Label string
+
+	ScreenWorkDuration time.Duration
+	OtherWorkDuration  time.Duration
+	WorkDuration       time.Duration
+	BreakDuration      time.Duration
+}
+
+// RangeStats are the statistics between two dates, From and To are inclusive.
+type RangeStats struct {
+	From, To time.Time
+	Totals   AllTimeStats
+	Days     []DailyStat
+	Outcomes OutcomeStats
+
+	Interruptions InterruptionStats
+}
+
+// InterruptionStats count the interruptions logged during sessions.
+type InterruptionStats struct {
+	Internal, External int
+}
+
+// Total returns the number of interruptions of both kinds.
+func (s InterruptionStats) Total() int {
+	return s.Internal + s.External
+}
+
+// OutcomeStats count how work sessions ended,
+// sessions recorded before outcomes were tracked are left out.
+type OutcomeStats struct {
+	Sessions                           int
+	Completed, Extended, Skipped, Quit int
+
+	// Overrun is the total time finished sessions ran longer than planned
+	Overrun time.Duration
+
+	// ActiveDays is the number of days with work sessions
+	ActiveDays int
+}
+
+// CompletionRate returns the share of sessions that ran to the end, between 0 and 1.
+func (s OutcomeStats) CompletionRate() float64 {
+	if s.Sessions == 0 {
+		return 0
+	}
+
+	return float64(s.Completed+s.Extended) / float64(s.Sessions)
+}
+
+// AverageOverrun returns the average time finished sessions ran longer than planned.
+func (s OutcomeStats) AverageOverrun() time.Duration {
+	finished := s.Completed + s.Extended
+	if finished == 0 {
+		return 0
+	}
+
+	return s.Overrun / time.Duration(finished)
+}
+
+// AbandonedPerDay returns the average number of skipped or quit sessions per active day.
+func (s OutcomeStats) AbandonedPerDay() float64 {
+	if s.ActiveDays == 0 {
+		return 0
+	}
+
+	return float64(s.Skipped+s.Quit) / float64(s.ActiveDays)
+}
+
+// HourlyStats describe when work happens, in the wall-clock time of the stats time zone.
+type HourlyStats struct {
+	// Work is the work duration by weekday and hour of the day
+	Work [7][24]time.Duration
+
+	// AverageStart is the average time of day work sessions start at,
+	// WeekdayAverageStarts is the same for each weekday
+	AverageStart         time.Duration
+	WeekdayAverageStarts [7]time.Duration
+
+	Sessions        int
+	WeekdaySessions [7]int
 }
 
 type StreakStats struct {
+	// daily streak in days
 	Current int
 	Best    int
+
+	// weekly streak in weeks
+	CurrentWeeks int
+	BestWeeks    int
+
+	// freezes left this month, only meaningful if FreezesPerMonth is set
+	FreezesLeft     int
+	FreezesPerMonth int
 }
 
 type SessionType string
 type SessionSource string
+type SessionOutcome string
+type InterruptionKind string
 
 const (
 	WorkSession  SessionType = "work"
@@ -51,6 +188,17 @@ const (
 
 	ScreenSource SessionSource = "screen"
 	OtherSource  SessionSource = "other"
+
+	CompletedOutcome SessionOutcome = "completed"
+	SkippedOutcome   SessionOutcome = "skipped"
+	QuitOutcome      SessionOutcome = "quit"
+	// the session was completed and then extended by a short session
+	ExtendedOutcome SessionOutcome = "extended"
+
+	// internal interruptions come from yourself, e.g. checking mail,
+	// external ones from others, e.g. a call
+	InternalInterruption InterruptionKind = "internal"
+	ExternalInterruption InterruptionKind = "external"
 )
 
 func GetSessionType(taskType config.TaskType) SessionType {
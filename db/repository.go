@@ -4,17 +4,29 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/Bahaaio/pomo/config"
 	"github.com/jmoiron/sqlx"
 )
 
 const DateFormat = "2006-01-02"
 
 type SessionRepo struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	calendar Calendar
+	settings config.Stats
 }
 
 func NewSessionRepo(db *sqlx.DB) *SessionRepo {
-	return &SessionRepo{db: db}
+	return &SessionRepo{
+		db:       db,
+		calendar: NewCalendar(config.C.Stats),
+		settings: config.C.Stats,
+	}
+}
+
+// Calendar returns the calendar sessions are grouped into days by.
+func (r *SessionRepo) Calendar() Calendar {
+	return r.calendar
 }
 
 // CreateSession inserts a new session record into the database.
@@ -29,62 +41,192 @@ func (r *SessionRepo) CreateSessionWithSource(
 	sessionType SessionType,
 	source SessionSource,
 ) error {
-	startedAtStr := startedAt.Format(time.RFC3339)
+	_, err := r.InsertSession(Session{
+		StartedAt: startedAt,
+		Duration:  duration,
+		Type:      string(sessionType),
+		Source:    string(source),
+	})
+
+	return err
+}
 
-	if _, err := r.db.Exec(
-		"insert into sessions (started_at, duration, type, source) values (?, ?, ?, ?);",
-		startedAtStr,
-		duration,
-		sessionType,
-		source,
-	); err != nil {
+// InsertSession inserts a fully specified session record into the database
+// and returns its id.
+// Times are stored as UTC unix seconds along with the time zone they were recorded in.
+func (r *SessionRepo) InsertSession(session Session) (int64, error) {
+	if session.Source == "" {
+		session.Source = string(ScreenSource)
+	}
+
+	zoneName, zoneOffset := session.StartedAt.Zone()
+
+	result, err := r.db.Exec(
+		`
+		INSERT INTO sessions (
+			started_at, ended_at, tz_name, tz_offset, duration, planned_duration,
+			type, source, profile, outcome, note, rating, todo_id
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+		`,
+		session.StartedAt.Unix(),
+		session.StartedAt.Add(session.Duration).Unix(),
+		zoneName,
+		zoneOffset,
+		session.Duration,
+		session.PlannedDuration,
+		session.Type,
+		session.Source,
+		session.Profile,
+		session.Outcome,
+		session.Note,
+		session.Rating,
+		session.TodoID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// SetSessionNote stores the note and rating written after the session with the given id.
+func (r *SessionRepo) SetSessionNote(id int64, note string, rating int) error {
+	result, err := r.db.Exec("UPDATE sessions SET note = ?, rating = ? WHERE id = ?;", note, rating, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
 		return err
 	}
 
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
 	return nil
 }
 
-// ExtendLatestSession adds duration to the latest session of the same type.
-func (r *SessionRepo) ExtendLatestSession(duration time.Duration, sessionType SessionType) error {
-	return r.ExtendLatestSessionBySource(duration, sessionType, ScreenSource)
+// InsertInterruptions stores the interruptions logged during the session with the given id.
+func (r *SessionRepo) InsertInterruptions(sessionID int64, interruptions []Interruption) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, interruption := range interruptions {
+		if _, err := tx.Exec(
+			"INSERT INTO interruptions (session_id, kind, note, occurred_at) VALUES (?, ?, ?, ?);",
+			sessionID,
+			interruption.Kind,
+			interruption.Note,
+			interruption.OccurredAt.Unix(),
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// InsertPauses stores the pauses of the session with the given id.
+func (r *SessionRepo) InsertPauses(sessionID int64, pauses []Pause) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, pause := range pauses {
+		if _, err := tx.Exec(
+			"INSERT INTO pauses (session_id, started_at, ended_at) VALUES (?, ?, ?);",
+			sessionID,
+			pause.StartedAt.Unix(),
+			pause.EndedAt.Unix(),
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetRecentSessions retrieves the latest sessions, newest first.
+// Start times are in the time zone of the calendar.
+func (r *SessionRepo) GetRecentSessions(limit int) ([]Session, error) {
+	var rows []struct {
+		Session
+		StartedAt int64 `db:"started_at"`
+	}
+
+	if err := r.db.Select(
+		&rows,
+		`
+		SELECT id, type, duration, planned_duration, started_at, source, profile, outcome, note, rating, todo_id
+		FROM sessions
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?;
+		`,
+		limit,
+	); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(rows))
+	for _, row := range rows {
+		session := row.Session
+		session.StartedAt = time.Unix(row.StartedAt, 0).In(r.calendar.location())
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// ExtendLatestSession adds duration to the latest session of the same type and profile
+// and returns its ID.
+func (r *SessionRepo) ExtendLatestSession(duration time.Duration, sessionType SessionType, profile string) (int64, error) {
+	return r.ExtendLatestSessionBySource(duration, sessionType, ScreenSource, profile)
 }
 
-// ExtendLatestSessionBySource adds duration to the latest session of the same type and source.
+// ExtendLatestSessionBySource adds duration to the latest session of the same type, source and profile
+// and returns its ID, or [sql.ErrNoRows] if there is none.
+// A completed session is marked as extended.
 func (r *SessionRepo) ExtendLatestSessionBySource(
 	duration time.Duration,
 	sessionType SessionType,
 	source SessionSource,
-) error {
-	result, err := r.db.Exec(
+	profile string,
+) (int64, error) {
+	var id int64
+	err := r.db.Get(
+		&id,
 		`
 		UPDATE sessions
-		SET duration = duration + ?
+		SET
+			duration = duration + ?1,
+			ended_at = started_at + (duration + ?1) / 1000000000,
+			outcome = CASE outcome WHEN ?4 THEN ?5 ELSE outcome END
 		WHERE id = (
 			SELECT id
 			FROM sessions
-			WHERE type = ? AND source = ?
+			WHERE type = ?2 AND source = ?3 AND profile = ?6
 			ORDER BY id DESC
 			LIMIT 1
-		);
+		)
+		RETURNING id;
 		`,
 		duration,
 		sessionType,
 		source,
+		CompletedOutcome,
+		ExtendedOutcome,
+		profile,
 	)
-	if err != nil {
-		return err
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
-	}
 
-	return nil
+	return id, err
 }
 
 // GetAllTimeStats retrieves aggregate statistics across all sessions.
@@ -108,93 +250,303 @@ func (r *SessionRepo) GetAllTimeStats() (AllTimeStats, error) {
 	return totalStats, nil
 }
 
-// GetWeeklyStats retrieves daily work duration statistics for the past 7 days.
-func (r *SessionRepo) GetWeeklyStats() ([]DailyStat, error) {
-	today := time.Now()
-	firstDay := today.AddDate(0, 0, -6)
+// GetProfileStats retrieves aggregate statistics grouped by config profile.
+// Sessions recorded without a profile are grouped under an empty name.
+func (r *SessionRepo) GetProfileStats() ([]ProfileStats, error) {
+	var stats []ProfileStats
 
-	return r.getDailyStats(firstDay, today)
+	if err := r.db.Select(
+		&stats,
+		`
+		SELECT
+			profile,
+			COUNT(*) AS total_sessions,
+			COALESCE(SUM(duration * (type = 'work')), 0) AS total_work_duration,
+			COALESCE(SUM(duration * (type = 'break')), 0) AS total_break_duration
+		FROM sessions
+		GROUP BY profile
+		ORDER BY total_work_duration DESC, profile;
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
 }
 
-// GetLastMonthsStats retrieves daily work duration statistics for the past specified number of months.
-func (r *SessionRepo) GetLastMonthsStats(numberOfMonths int) ([]DailyStat, error) {
-	today := time.Now()
-	firstDay := today.AddDate(0, -numberOfMonths, -today.Day()+1)
+// GetRangeStats retrieves the totals and daily statistics between the specified dates.
+// from and to are inclusive.
+func (r *SessionRepo) GetRangeStats(from, to time.Time) (RangeStats, error) {
+	from, to = r.calendar.Date(from), r.calendar.Date(to)
+
+	days, sessions, err := r.collectDailyStats(from, to)
+	if err != nil {
+		return RangeStats{}, err
+	}
+
+	outcomes, err := r.collectOutcomeStats(from, to)
+	if err != nil {
+		return RangeStats{}, err
+	}
+
+	interruptions, err := r.collectInterruptionStats(from, to)
+	if err != nil {
+		return RangeStats{}, err
+	}
+
+	stats := RangeStats{From: from, To: to, Days: days, Outcomes: outcomes, Interruptions: interruptions}
+	stats.Totals.TotalSessions = sessions
 
-	return r.getDailyStats(firstDay, today)
+	for _, day := range days {
+		stats.Totals.TotalWorkDuration += day.WorkDuration
+		stats.Totals.TotalBreakDuration += day.BreakDuration
+	}
+
+	return stats, nil
 }
 
-// GetStreakStats calculates the current and best streaks of consecutive work days.
-// A streak is consecutive days with at least one 'work' session.
-func (r *SessionRepo) GetStreakStats() (StreakStats, error) {
-	var dates []string
+// GetDailyStats retrieves daily statistics between the specified dates.
+// from and to are inclusive.
+// The results are normalized to include all days in the range.
+func (r *SessionRepo) GetDailyStats(from, to time.Time) ([]DailyStat, error) {
+	days, _, err := r.collectDailyStats(r.calendar.Date(from), r.calendar.Date(to))
+	return days, err
+}
+
+// GetHourlyStats retrieves when screen work sessions started between the specified dates happen.
+// from and to are inclusive.
+// Manually added work is left out since it isn't tied to a time of day.
+func (r *SessionRepo) GetHourlyStats(from, to time.Time) (HourlyStats, error) {
+	from, to = r.calendar.Date(from), r.calendar.Date(to)
+
+	var sessions []struct {
+		StartedAt int64         `db:"started_at"`
+		Duration  time.Duration `db:"duration"`
+	}
 
 	if err := r.db.Select(
-		&dates,
+		&sessions,
 		`
-		SELECT DISTINCT date(started_at, 'localtime') AS day
+		SELECT started_at, duration
 		FROM sessions
-		WHERE type = 'work'
-		ORDER BY day DESC;
+		WHERE type = ? AND source = ? AND started_at >= ? AND started_at < ?;
 		`,
+		WorkSession,
+		ScreenSource,
+		r.calendar.Start(from).Unix(),
+		r.calendar.Start(to.AddDate(0, 0, 1)).Unix(),
+	); err != nil {
+		return HourlyStats{}, err
+	}
+
+	spans := make([]workSpan, 0, len(sessions))
+	for _, session := range sessions {
+		spans = append(spans, workSpan{StartedAt: time.Unix(session.StartedAt, 0), Duration: session.Duration})
+	}
+
+	return calculateHourlyStats(spans, r.calendar), nil
+}
+
+// GetMonthsStats retrieves daily statistics of the last numberOfMonths months up to end,
+// from the first day of the earliest month.
+func (r *SessionRepo) GetMonthsStats(end time.Time, numberOfMonths int) ([]DailyStat, error) {
+	firstDay := time.Date(end.Year(), end.Month()-time.Month(numberOfMonths-1), 1, 0, 0, 0, 0, end.Location())
+	return r.GetDailyStats(firstDay, end)
+}
+
+// GetStreakStats calculates the current and best daily and weekly streaks
+// following the configured streak rules.
+func (r *SessionRepo) GetStreakStats() (StreakStats, error) {
+	var firstStartedAt sql.NullInt64
+
+	if err := r.db.Get(
+		&firstStartedAt,
+		"SELECT MIN(started_at) FROM sessions WHERE type = 'work';",
 	); err != nil {
 		return StreakStats{}, err
 	}
 
-	return calculateStreak(dates), nil
+	today := r.calendar.Today()
+	rules := r.settings.Streak
+
+	if !firstStartedAt.Valid {
+		return calculateStreak(nil, today, r.calendar, rules), nil
+	}
+
+	firstDay := r.calendar.Date(time.Unix(firstStartedAt.Int64, 0))
+	if firstDay.After(today) {
+		firstDay = today
+	}
+
+	stats, err := r.GetDailyStats(firstDay, today)
+	if err != nil {
+		return StreakStats{}, err
+	}
+
+	return calculateStreak(stats, today, r.calendar, rules), nil
 }
 
-// retrieves daily work duration statistics between the specified dates.
-// from and to are inclusive.
-// The results are normalized to include all days in the range.
-func (r *SessionRepo) getDailyStats(from, to time.Time) ([]DailyStat, error) {
-	fromStr := from.Format(DateFormat)
-	toStr := to.Format(DateFormat)
+// collects the daily statistics between the specified dates
+// and the number of sessions they include.
+// from and to must be dates of the calendar.
+func (r *SessionRepo) collectDailyStats(from, to time.Time) ([]DailyStat, int, error) {
+	// split sessions count toward the range if they end in it
+	condition := "started_at >= ?"
+	if r.settings.SplitSessions {
+		condition = "ended_at > ?"
+	}
 
-	var stats []DailyStat
+	var sessions []struct {
+		Type      string        `db:"type"`
+		StartedAt int64         `db:"started_at"`
+		Duration  time.Duration `db:"duration"`
+		Source    string        `db:"source"`
+	}
 
 	if err := r.db.Select(
-		&stats,
+		&sessions,
 		`
-		SELECT
-			date(started_at, 'localtime') AS day,
-			COALESCE(SUM(duration * (type = 'work' AND source = 'screen')), 0) AS screen_work_duration,
-			COALESCE(SUM(duration * (type = 'work' AND source = 'other')), 0) AS other_work_duration,
-			COALESCE(SUM(duration * (type = 'work')), 0) AS work_duration
+		SELECT type, started_at, duration, source
 		FROM sessions
-		WHERE date(started_at, 'localtime') BETWEEN ? AND ?
-		GROUP BY day
-		ORDER BY day;
+		WHERE `+condition+` AND started_at < ?;
 		`,
-		fromStr, toStr,
+		r.calendar.Start(from).Unix(),
+		r.calendar.Start(to.AddDate(0, 0, 1)).Unix(),
 	); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return r.normalizeStats(from, to, stats), nil
+	// days are computed here rather than in sqlite so they follow the configured time zone
+	byDay := make(map[string]DailyStat)
+	fromKey, toKey := from.Format(DateFormat), to.Format(DateFormat)
+	count := 0
+
+	for _, session := range sessions {
+		startedAt := time.Unix(session.StartedAt, 0)
+
+		parts := []DayPart{{Date: r.calendar.Date(startedAt), Duration: session.Duration}}
+		if r.settings.SplitSessions {
+			parts = r.calendar.Split(startedAt, session.Duration)
+		}
+
+		counted := false
+		for _, part := range parts {
+			day := part.Date.Format(DateFormat)
+			if day < fromKey || day > toKey {
+				continue
+			}
+
+			stat := byDay[day]
+			switch SessionType(session.Type) {
+			case WorkSession:
+				stat.WorkDuration += part.Duration
+				switch SessionSource(session.Source) {
+				case ScreenSource:
+					stat.ScreenWorkDuration += part.Duration
+				case OtherSource:
+					stat.OtherWorkDuration += part.Duration
+				}
+			case BreakSession:
+				stat.BreakDuration += part.Duration
+			}
+			byDay[day] = stat
+
+			counted = true
+		}
+
+		if counted {
+			count++
+		}
+	}
+
+	return normalizeStats(from, to, byDay), count, nil
 }
 
-// ensures that there is a DailyStat entry for each day
-func (r *SessionRepo) normalizeStats(from, to time.Time, stats []DailyStat) []DailyStat {
-	m := make(map[string]DailyStat)
+// collects how the screen work sessions started between the specified dates ended.
+// from and to must be dates of the calendar.
+func (r *SessionRepo) collectOutcomeStats(from, to time.Time) (OutcomeStats, error) {
+	var sessions []struct {
+		StartedAt       int64         `db:"started_at"`
+		Duration        time.Duration `db:"duration"`
+		PlannedDuration time.Duration `db:"planned_duration"`
+		Outcome         string        `db:"outcome"`
+	}
 
-	for _, stat := range stats {
-		m[stat.Date] = stat
+	if err := r.db.Select(
+		&sessions,
+		`
+		SELECT started_at, duration, planned_duration, outcome
+		FROM sessions
+		WHERE type = ? AND source = ? AND outcome != '' AND started_at >= ? AND started_at < ?;
+		`,
+		WorkSession,
+		ScreenSource,
+		r.calendar.Start(from).Unix(),
+		r.calendar.Start(to.AddDate(0, 0, 1)).Unix(),
+	); err != nil {
+		return OutcomeStats{}, err
+	}
+
+	var stats OutcomeStats
+	activeDays := make(map[string]bool)
+
+	for _, session := range sessions {
+		stats.Sessions++
+		activeDays[r.calendar.Date(time.Unix(session.StartedAt, 0)).Format(DateFormat)] = true
+
+		switch SessionOutcome(session.Outcome) {
+		case CompletedOutcome:
+			stats.Completed++
+		case ExtendedOutcome:
+			stats.Extended++
+		case SkippedOutcome:
+			stats.Skipped++
+		case QuitOutcome:
+			stats.Quit++
+		}
+
+		finished := session.Outcome == string(CompletedOutcome) || session.Outcome == string(ExtendedOutcome)
+		if finished && session.PlannedDuration > 0 && session.Duration > session.PlannedDuration {
+			stats.Overrun += session.Duration - session.PlannedDuration
+		}
 	}
 
+	stats.ActiveDays = len(activeDays)
+
+	return stats, nil
+}
+
+func (r *SessionRepo) collectInterruptionStats(from, to time.Time) (InterruptionStats, error) {
+	var stats InterruptionStats
+
+	// sqlite treats (kind = 'internal') as 1 or 0
+	err := r.db.QueryRowx(
+		`
+		SELECT
+			COALESCE(SUM(kind = ?), 0),
+			COALESCE(SUM(kind = ?), 0)
+		FROM interruptions
+		WHERE occurred_at >= ? AND occurred_at < ?;
+		`,
+		InternalInterruption,
+		ExternalInterruption,
+		r.calendar.Start(from).Unix(),
+		r.calendar.Start(to.AddDate(0, 0, 1)).Unix(),
+	).Scan(&stats.Internal, &stats.External)
+
+	return stats, err
+}
+
+// ensures that there is a DailyStat entry for each day
+func normalizeStats(from, to time.Time, byDay map[string]DailyStat) []DailyStat {
 	var normalized []DailyStat
-	current := from
-	for !current.After(to) {
-		day := current.Format(DateFormat)
 
-		normalized = append(normalized, DailyStat{
-			Date:               day,
-			ScreenWorkDuration: m[day].ScreenWorkDuration,
-			OtherWorkDuration:  m[day].OtherWorkDuration,
-			WorkDuration:       m[day].WorkDuration,
-		})
+	for current := from; !current.After(to); current = current.AddDate(0, 0, 1) {
+		day := current.Format(DateFormat)
 
-		current = current.AddDate(0, 0, 1) // next day
+		stat := byDay[day]
+		stat.Date = day
+		normalized = append(normalized, stat)
 	}
 
 	return normalized
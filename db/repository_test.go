@@ -16,8 +16,11 @@ func newTestRepo(t *testing.T) *SessionRepo {
 		t.Fatalf("open sqlite: %v", err)
 	}
 
-	if _, err := database.Exec(schema); err != nil {
-		t.Fatalf("create schema: %v", err)
+	// every connection to :memory: opens a new database
+	database.SetMaxOpenConns(1)
+
+	if _, err := Migrate(database, ""); err != nil {
+		t.Fatalf("migrate: %v", err)
 	}
 
 	return NewSessionRepo(database)
@@ -31,7 +34,7 @@ func TestExtendLatestSession(t *testing.T) {
 		t.Fatalf("create session: %v", err)
 	}
 
-	if err := repo.ExtendLatestSession(27*time.Minute, WorkSession); err != nil {
+	if _, err := repo.ExtendLatestSession(27*time.Minute, WorkSession, ""); err != nil {
 		t.Fatalf("extend latest session: %v", err)
 	}
 
@@ -48,7 +51,7 @@ func TestExtendLatestSession(t *testing.T) {
 func TestExtendLatestSession_NoRows(t *testing.T) {
 	repo := newTestRepo(t)
 
-	err := repo.ExtendLatestSession(10*time.Minute, WorkSession)
+	_, err := repo.ExtendLatestSession(10*time.Minute, WorkSession, "")
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
@@ -69,7 +72,7 @@ func TestGetDailyStats_SplitsScreenAndOther(t *testing.T) {
 		t.Fatalf("create other session: %v", err)
 	}
 
-	stats, err := repo.getDailyStats(day, day)
+	stats, err := repo.GetDailyStats(day, day)
 	if err != nil {
 		t.Fatalf("get daily stats: %v", err)
 	}
@@ -87,3 +90,470 @@ func TestGetDailyStats_SplitsScreenAndOther(t *testing.T) {
 		t.Fatalf("work duration = %v, want %v", stats[0].WorkDuration, time.Hour+27*time.Minute)
 	}
 }
+
+func TestGetProfileStats(t *testing.T) {
+	repo := newTestRepo(t)
+	day := time.Date(2026, 2, 14, 9, 0, 0, 0, time.Local)
+
+	sessions := []Session{
+		{StartedAt: day, Duration: time.Hour, Type: string(WorkSession), Profile: "deep"},
+		{StartedAt: day, Duration: 30 * time.Minute, Type: string(WorkSession), Profile: "deep"},
+		{StartedAt: day, Duration: 10 * time.Minute, Type: string(BreakSession), Profile: "deep"},
+		{StartedAt: day, Duration: 25 * time.Minute, Type: string(WorkSession)},
+	}
+	for _, session := range sessions {
+		if _, err := repo.InsertSession(session); err != nil {
+			t.Fatalf("insert session: %v", err)
+		}
+	}
+
+	stats, err := repo.GetProfileStats()
+	if err != nil {
+		t.Fatalf("get profile stats: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 profiles, got %d", len(stats))
+	}
+
+	if stats[0].Profile != "deep" || stats[0].TotalWorkDuration != 90*time.Minute || stats[0].TotalSessions != 3 {
+		t.Fatalf("deep stats = %+v", stats[0])
+	}
+	if stats[1].Profile != "" || stats[1].TotalWorkDuration != 25*time.Minute {
+		t.Fatalf("default stats = %+v", stats[1])
+	}
+}
+
+func TestGetDailyStats_UsesCalendarTimeZone(t *testing.T) {
+	repo := newTestRepo(t)
+
+	// 23:30 UTC is already the next day two hours east
+	startedAt := time.Date(2026, 2, 13, 23, 30, 0, 0, time.UTC)
+	if err := repo.CreateSession(startedAt, 25*time.Minute, WorkSession); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		location *time.Location
+		day      string
+	}{
+		{"utc", time.UTC, "2026-02-13"},
+		{"east of utc", time.FixedZone("UTC+2", 2*60*60), "2026-02-14"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.calendar = Calendar{Location: tt.location}
+
+			from := time.Date(2026, 2, 12, 12, 0, 0, 0, tt.location)
+			stats, err := repo.GetDailyStats(from, from.AddDate(0, 0, 3))
+			if err != nil {
+				t.Fatalf("get daily stats: %v", err)
+			}
+
+			for _, stat := range stats {
+				want := time.Duration(0)
+				if stat.Date == tt.day {
+					want = 25 * time.Minute
+				}
+
+				if stat.WorkDuration != want {
+					t.Fatalf("work duration on %s = %v, want %v", stat.Date, stat.WorkDuration, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExtendLatestSession_SameProfile(t *testing.T) {
+	repo := newTestRepo(t)
+	start := time.Date(2026, 2, 13, 9, 0, 0, 0, time.UTC)
+
+	ids := map[string]int64{}
+	for _, profile := range []string{"deep", ""} {
+		id, err := repo.InsertSession(Session{
+			StartedAt: start,
+			Duration:  time.Hour,
+			Type:      string(WorkSession),
+			Profile:   profile,
+		})
+		if err != nil {
+			t.Fatalf("insert session: %v", err)
+		}
+		ids[profile] = id
+	}
+
+	// the session of the profile is extended, not the latest one
+	id, err := repo.ExtendLatestSession(27*time.Minute, WorkSession, "deep")
+	if err != nil {
+		t.Fatalf("extend latest session: %v", err)
+	}
+	if id != ids["deep"] {
+		t.Fatalf("extended session %d, want %d", id, ids["deep"])
+	}
+
+	stats, err := repo.GetProfileStats()
+	if err != nil {
+		t.Fatalf("get profile stats: %v", err)
+	}
+
+	for _, stat := range stats {
+		want := time.Hour
+		if stat.Profile == "deep" {
+			want += 27 * time.Minute
+		}
+
+		if stat.TotalWorkDuration != want {
+			t.Fatalf("work duration of profile %q = %v, want %v", stat.Profile, stat.TotalWorkDuration, want)
+		}
+	}
+
+	if _, err := repo.ExtendLatestSession(time.Minute, WorkSession, "other"); err != sql.ErrNoRows {
+		t.Fatalf("error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestExtendLatestSession_UpdatesEndedAt(t *testing.T) {
+	repo := newTestRepo(t)
+	start := time.Date(2026, 2, 13, 9, 0, 0, 0, time.UTC)
+
+	if err := repo.CreateSession(start, time.Hour, WorkSession); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	if _, err := repo.ExtendLatestSession(27*time.Minute, WorkSession, ""); err != nil {
+		t.Fatalf("extend latest session: %v", err)
+	}
+
+	var endedAt int64
+	if err := repo.db.Get(&endedAt, "SELECT ended_at FROM sessions;"); err != nil {
+		t.Fatalf("get ended_at: %v", err)
+	}
+
+	if want := start.Add(time.Hour + 27*time.Minute).Unix(); endedAt != want {
+		t.Fatalf("ended_at = %d, want %d", endedAt, want)
+	}
+}
+
+func TestGetStreakStats_DayStartsAt(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.calendar = Calendar{Location: time.Local, DayStartsAt: 4 * time.Hour}
+
+	// 22:00-23:00 yesterday and 00:30 today count as the same day
+	today := repo.calendar.Today()
+	yesterday := today.AddDate(0, 0, -1)
+
+	for _, startedAt := range []time.Time{
+		yesterday.Add(22 * time.Hour),
+		yesterday.Add(24*time.Hour + 30*time.Minute),
+		yesterday.AddDate(0, 0, -1).Add(23 * time.Hour),
+	} {
+		if err := repo.CreateSession(startedAt, 25*time.Minute, WorkSession); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+	}
+
+	streak, err := repo.GetStreakStats()
+	if err != nil {
+		t.Fatalf("get streak stats: %v", err)
+	}
+
+	if streak.Current != 2 || streak.Best != 2 {
+		t.Fatalf("streak = %+v, want current and best of 2", streak)
+	}
+}
+
+func TestGetDailyStats_SplitSessions(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.calendar = Calendar{Location: time.UTC}
+
+	// 23:40 for 50 minutes
+	startedAt := time.Date(2026, 2, 13, 23, 40, 0, 0, time.UTC)
+	if err := repo.CreateSession(startedAt, 50*time.Minute, WorkSession); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	testCases := []struct {
+		name  string
+		split bool
+		want  map[string]time.Duration
+	}{
+		{"start day", false, map[string]time.Duration{"2026-02-13": 50 * time.Minute}},
+		{"split", true, map[string]time.Duration{"2026-02-13": 20 * time.Minute, "2026-02-14": 30 * time.Minute}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.settings.SplitSessions = tt.split
+
+			day := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+			stats, err := repo.GetDailyStats(day.AddDate(0, 0, -1), day)
+			if err != nil {
+				t.Fatalf("get daily stats: %v", err)
+			}
+
+			for _, stat := range stats {
+				if stat.WorkDuration != tt.want[stat.Date] || stat.ScreenWorkDuration != tt.want[stat.Date] {
+					t.Fatalf("work duration on %s = %v, want %v", stat.Date, stat.WorkDuration, tt.want[stat.Date])
+				}
+			}
+
+			// the second day alone still gets its part
+			stats, err = repo.GetDailyStats(day, day)
+			if err != nil {
+				t.Fatalf("get daily stats: %v", err)
+			}
+			if len(stats) != 1 || stats[0].WorkDuration != tt.want["2026-02-14"] {
+				t.Fatalf("second day stats = %+v, want %v", stats, tt.want["2026-02-14"])
+			}
+		})
+	}
+}
+
+func TestGetMonthsStats(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.calendar = Calendar{Location: time.UTC}
+
+	end := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	stats, err := repo.GetMonthsStats(end, 12)
+	if err != nil {
+		t.Fatalf("get months stats: %v", err)
+	}
+
+	if len(stats) != 365 || stats[0].Date != "2025-01-01" || stats[len(stats)-1].Date != "2025-12-31" {
+		t.Fatalf("got %d days from %s to %s, want all of 2025", len(stats), stats[0].Date, stats[len(stats)-1].Date)
+	}
+}
+
+func TestGetRangeStats(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.calendar = Calendar{Location: time.UTC}
+	day := time.Date(2026, 2, 14, 9, 0, 0, 0, time.UTC)
+
+	sessions := []Session{
+		{StartedAt: day, Duration: time.Hour, Type: string(WorkSession)},
+		{StartedAt: day.Add(time.Hour), Duration: 5 * time.Minute, Type: string(BreakSession)},
+		{StartedAt: day.AddDate(0, 0, 1), Duration: 30 * time.Minute, Type: string(WorkSession), Source: string(OtherSource)},
+		// outside of the range
+		{StartedAt: day.AddDate(0, 0, -1), Duration: 2 * time.Hour, Type: string(WorkSession)},
+	}
+	for _, session := range sessions {
+		if _, err := repo.InsertSession(session); err != nil {
+			t.Fatalf("insert session: %v", err)
+		}
+	}
+
+	stats, err := repo.GetRangeStats(day, day.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("get range stats: %v", err)
+	}
+
+	want := AllTimeStats{TotalSessions: 3, TotalWorkDuration: 90 * time.Minute, TotalBreakDuration: 5 * time.Minute}
+	if stats.Totals != want {
+		t.Fatalf("totals = %+v, want %+v", stats.Totals, want)
+	}
+
+	if len(stats.Days) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(stats.Days))
+	}
+	if stats.Days[0].BreakDuration != 5*time.Minute || stats.Days[1].OtherWorkDuration != 30*time.Minute {
+		t.Fatalf("days = %+v", stats.Days)
+	}
+}
+
+func TestGetHourlyStats_SkipsManualWork(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.calendar = Calendar{Location: time.UTC}
+	start := time.Date(2026, 2, 13, 9, 30, 0, 0, time.UTC)
+
+	if err := repo.CreateSessionWithSource(start, time.Hour, WorkSession, ScreenSource); err != nil {
+		t.Fatalf("create screen session: %v", err)
+	}
+	if err := repo.CreateSessionWithSource(start, 27*time.Minute, WorkSession, OtherSource); err != nil {
+		t.Fatalf("create other session: %v", err)
+	}
+	if err := repo.CreateSession(start.Add(time.Hour), 5*time.Minute, BreakSession); err != nil {
+		t.Fatalf("create break session: %v", err)
+	}
+
+	stats, err := repo.GetHourlyStats(start, start)
+	if err != nil {
+		t.Fatalf("get hourly stats: %v", err)
+	}
+
+	if stats.Sessions != 1 {
+		t.Fatalf("sessions = %d, want 1", stats.Sessions)
+	}
+
+	friday := stats.Work[time.Friday]
+	if friday[9] != 30*time.Minute || friday[10] != 30*time.Minute {
+		t.Fatalf("work at 9:00 and 10:00 = %v and %v, want 30m each", friday[9], friday[10])
+	}
+
+	if stats.AverageStart != 9*time.Hour+30*time.Minute {
+		t.Fatalf("average start = %v, want 9h30m", stats.AverageStart)
+	}
+}
+
+func TestGetRangeStats_Outcomes(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.calendar = Calendar{Location: time.UTC}
+	day := time.Date(2026, 2, 13, 9, 0, 0, 0, time.UTC)
+
+	sessions := []Session{
+		{StartedAt: day, Duration: 25 * time.Minute, PlannedDuration: 25 * time.Minute, Outcome: string(CompletedOutcome)},
+		{StartedAt: day.Add(time.Hour), Duration: 28 * time.Minute, PlannedDuration: 25 * time.Minute, Outcome: string(CompletedOutcome)},
+		{StartedAt: day.Add(2 * time.Hour), Duration: 10 * time.Minute, PlannedDuration: 25 * time.Minute, Outcome: string(SkippedOutcome)},
+		{StartedAt: day.AddDate(0, 0, 1), Duration: 5 * time.Minute, PlannedDuration: 25 * time.Minute, Outcome: string(QuitOutcome)},
+		// recorded before outcomes were tracked
+		{StartedAt: day.AddDate(0, 0, 1), Duration: 25 * time.Minute},
+	}
+
+	for _, session := range sessions {
+		session.Type = string(WorkSession)
+		if _, err := repo.InsertSession(session); err != nil {
+			t.Fatalf("insert session: %v", err)
+		}
+	}
+
+	// extends the latest completed session by 2 minutes
+	if _, err := repo.InsertSession(Session{
+		StartedAt:       day.AddDate(0, 0, 1).Add(time.Hour),
+		Duration:        25 * time.Minute,
+		PlannedDuration: 25 * time.Minute,
+		Type:            string(WorkSession),
+		Outcome:         string(CompletedOutcome),
+	}); err != nil {
+		t.Fatalf("insert session: %v", err)
+	}
+	if _, err := repo.ExtendLatestSession(2*time.Minute, WorkSession, ""); err != nil {
+		t.Fatalf("extend latest session: %v", err)
+	}
+
+	stats, err := repo.GetRangeStats(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("get range stats: %v", err)
+	}
+
+	want := OutcomeStats{
+		Sessions:   5,
+		Completed:  2,
+		Extended:   1,
+		Skipped:    1,
+		Quit:       1,
+		Overrun:    5 * time.Minute,
+		ActiveDays: 2,
+	}
+	if stats.Outcomes != want {
+		t.Fatalf("outcomes = %+v, want %+v", stats.Outcomes, want)
+	}
+
+	if got := stats.Outcomes.CompletionRate(); got != 0.6 {
+		t.Fatalf("completion rate = %v, want 0.6", got)
+	}
+	if got := stats.Outcomes.AverageOverrun(); got != 5*time.Minute/3 {
+		t.Fatalf("average overrun = %v, want %v", got, 5*time.Minute/3)
+	}
+	if got := stats.Outcomes.AbandonedPerDay(); got != 1 {
+		t.Fatalf("interruptions per day = %v, want 1", got)
+	}
+}
+
+func TestSetSessionNote(t *testing.T) {
+	repo := newTestRepo(t)
+	start := time.Date(2026, 2, 13, 9, 0, 0, 0, time.UTC)
+
+	id, err := repo.InsertSession(Session{StartedAt: start, Duration: 25 * time.Minute, Type: string(WorkSession)})
+	if err != nil {
+		t.Fatalf("insert session: %v", err)
+	}
+	if _, err := repo.InsertSession(Session{StartedAt: start.Add(time.Hour), Duration: 5 * time.Minute, Type: string(BreakSession)}); err != nil {
+		t.Fatalf("insert session: %v", err)
+	}
+
+	if err := repo.SetSessionNote(id, "wrote the parser", 4); err != nil {
+		t.Fatalf("set session note: %v", err)
+	}
+
+	if err := repo.SetSessionNote(id+100, "missing", 1); err != sql.ErrNoRows {
+		t.Fatalf("set note of missing session error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	sessions, err := repo.GetRecentSessions(10)
+	if err != nil {
+		t.Fatalf("get recent sessions: %v", err)
+	}
+
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+
+	// newest first
+	if sessions[0].Type != string(BreakSession) {
+		t.Fatalf("first session type = %q, want the break", sessions[0].Type)
+	}
+
+	work := sessions[1]
+	if work.Note != "wrote the parser" || work.Rating != 4 {
+		t.Fatalf("note = %q rating = %d, want the saved note and rating", work.Note, work.Rating)
+	}
+	if !work.StartedAt.Equal(start) || work.Duration != 25*time.Minute {
+		t.Fatalf("session = %+v, want started at %v lasting 25m", work, start)
+	}
+}
+
+func TestInsertInterruptions(t *testing.T) {
+	repo := newTestRepo(t)
+	start := time.Date(2026, 2, 13, 9, 0, 0, 0, time.Local)
+
+	id, err := repo.InsertSession(Session{StartedAt: start, Duration: 25 * time.Minute, Type: string(WorkSession)})
+	if err != nil {
+		t.Fatalf("insert session: %v", err)
+	}
+
+	if err := repo.InsertInterruptions(id, []Interruption{
+		{Kind: InternalInterruption, Note: "checked mail", OccurredAt: start.Add(5 * time.Minute)},
+		{Kind: ExternalInterruption, Note: "phone call", OccurredAt: start.Add(10 * time.Minute)},
+		{Kind: ExternalInterruption, OccurredAt: start.Add(15 * time.Minute)},
+		// the next day
+		{Kind: InternalInterruption, OccurredAt: start.AddDate(0, 0, 1)},
+	}); err != nil {
+		t.Fatalf("insert interruptions: %v", err)
+	}
+
+	stats, err := repo.GetRangeStats(start, start)
+	if err != nil {
+		t.Fatalf("get range stats: %v", err)
+	}
+
+	want := InterruptionStats{Internal: 1, External: 2}
+	if stats.Interruptions != want {
+		t.Fatalf("interruptions = %+v, want %+v", stats.Interruptions, want)
+	}
+
+	var linked int
+	if err := repo.db.Get(&linked, "SELECT COUNT(*) FROM interruptions WHERE session_id = ?;", id); err != nil || linked != 4 {
+		t.Fatalf("interruptions linked to the session = %d (%v), want 4", linked, err)
+	}
+}
+
+func TestInsertPauses(t *testing.T) {
+	repo := newTestRepo(t)
+	start := time.Date(2026, 2, 13, 9, 0, 0, 0, time.Local)
+
+	if err := repo.InsertPauses(7, []Pause{
+		{StartedAt: start, EndedAt: start.Add(2 * time.Minute)},
+		{StartedAt: start.Add(10 * time.Minute), EndedAt: start.Add(11 * time.Minute)},
+	}); err != nil {
+		t.Fatalf("insert pauses: %v", err)
+	}
+
+	var paused int64
+	if err := repo.db.Get(&paused, "SELECT SUM(ended_at - started_at) FROM pauses WHERE session_id = 7;"); err != nil {
+		t.Fatalf("sum pauses: %v", err)
+	}
+
+	if paused != 3*60 {
+		t.Fatalf("paused seconds = %d, want %d", paused, 3*60)
+	}
+}
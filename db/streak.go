@@ -2,59 +2,88 @@ package db
 
 import (
 	"time"
+
+	"github.com/Bahaaio/pomo/config"
 )
 
-// calculateStreak calculates the current and best streaks from a list of dates (formatted as "YYYY-MM-DD").
-// the dates should be sorted in descending order (most recent first).
-// a streak is consecutive days with at least one 'work' session.
-func calculateStreak(dates []string) StreakStats {
-	now := time.Now()
-	today := now.Format(DateFormat)
-	yesterday := now.AddDate(0, 0, -1).Format(DateFormat)
-
-	currentStreak, bestStreak, tempStreak := 0, 0, 0
-	currentStreakBroken := false
-
-	for i, date := range dates {
-		if i == 0 {
-			// if the most recent date is today or yesterday, start the streak
-			if date == today || date == yesterday {
-				tempStreak = 1
-				currentStreak = 1
-				bestStreak = 1
-			} else {
-				// streak is already broken
-				currentStreakBroken = true
-				tempStreak = 1
-			}
+const monthFormat = "2006-01"
+
+// calculateStreak calculates the current and best daily and weekly streaks.
+// stats must cover every day up to today in ascending order, like getDailyStats returns them.
+//
+// A day counts toward the streak if it has at least the minimum work duration.
+// Rest days and today neither extend nor break a streak,
+// and a limited number of missed days per month can be frozen instead of breaking it.
+// A week counts toward the weekly streak if it has enough counted days.
+func calculateStreak(stats []DailyStat, today time.Time, calendar Calendar, rules config.Streak) StreakStats {
+	streak := StreakStats{FreezesPerMonth: rules.FreezesPerMonth}
+
+	restDays := make(map[time.Weekday]bool)
+	for _, name := range rules.RestDays {
+		if weekday, err := config.ParseWeekday(name); err == nil {
+			restDays[weekday] = true
+		}
+	}
+
+	// dates are compared in UTC like the parsed stats
+	todayKey := today.Format(DateFormat)
+	today, _ = time.Parse(DateFormat, todayKey)
+	freezesUsed := make(map[string]int) // by month
+	countedDays := make(map[string]int) // by week start
+
+	var firstDate time.Time
+
+	for _, stat := range stats {
+		date, err := time.Parse(DateFormat, stat.Date)
+		if err != nil {
 			continue
 		}
 
-		if isConsecutiveDate(dates[i-1], date) {
-			tempStreak++
-			bestStreak = max(bestStreak, tempStreak)
-
-			if !currentStreakBroken {
-				currentStreak++
-			}
-		} else {
-			// streak broken
-			currentStreakBroken = true
-			tempStreak = 1
+		if firstDate.IsZero() {
+			firstDate = date
+		}
+
+		month := date.Format(monthFormat)
+
+		switch {
+		case stat.WorkDuration > 0 && stat.WorkDuration >= rules.MinDuration:
+			streak.Current++
+			streak.Best = max(streak.Best, streak.Current)
+			countedDays[calendar.WeekStart(date).Format(DateFormat)]++
+
+		case stat.Date == todayKey || restDays[date.Weekday()]:
+			// today isn't over yet and rest days are skipped
+
+		case streak.Current > 0 && freezesUsed[month] < rules.FreezesPerMonth:
+			freezesUsed[month]++
+
+		default:
+			streak.Current = 0
 		}
 	}
 
-	return StreakStats{Current: currentStreak, Best: bestStreak}
-}
+	streak.FreezesLeft = max(0, rules.FreezesPerMonth-freezesUsed[today.Format(monthFormat)])
 
-// checks if date1 is the day after date2
-func isConsecutiveDate(date1, date2 string) bool {
-	t1, err1 := time.Parse(DateFormat, date1)
-	t2, err2 := time.Parse(DateFormat, date2)
+	if firstDate.IsZero() {
+		return streak
+	}
+
+	minDays := max(1, rules.WeeklyMinDays)
+	currentWeek := calendar.WeekStart(today)
+
+	for week := calendar.WeekStart(firstDate); !week.After(currentWeek); week = week.AddDate(0, 0, 7) {
+		switch {
+		case countedDays[week.Format(DateFormat)] >= minDays:
+			streak.CurrentWeeks++
+			streak.BestWeeks = max(streak.BestWeeks, streak.CurrentWeeks)
 
-	if err1 != nil || err2 != nil {
-		return false
+		case week.Equal(currentWeek):
+			// the current week isn't over yet
+
+		default:
+			streak.CurrentWeeks = 0
+		}
 	}
 
-	return t1.AddDate(0, 0, -1).Equal(t2)
+	return streak
 }
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+// builds daily stats from the first worked day up to today,
+// work maps days before today to the work done on them
+func buildStreakStats(today time.Time, work map[int]time.Duration) []DailyStat {
+	first := 0
+	for daysAgo := range work {
+		first = max(first, daysAgo)
+	}
+
+	var stats []DailyStat
+	for daysAgo := first; daysAgo >= 0; daysAgo-- {
+		stats = append(stats, DailyStat{
+			Date:         today.AddDate(0, 0, -daysAgo).Format(DateFormat),
+			WorkDuration: work[daysAgo],
+		})
+	}
+
+	return stats
+}
+
+func TestCalculateStreak(t *testing.T) {
+	// a wednesday
+	today := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	hour := time.Hour
+
+	testCases := []struct {
+		name  string
+		work  map[int]time.Duration
+		rules config.Streak
+		want  StreakStats
+	}{
+		{
+			name: "no sessions",
+			work: nil,
+			want: StreakStats{},
+		},
+		{
+			name: "today not worked yet keeps the streak",
+			work: map[int]time.Duration{1: hour, 2: hour, 3: hour},
+			want: StreakStats{Current: 3, Best: 3, CurrentWeeks: 2, BestWeeks: 2},
+		},
+		{
+			name: "missed yesterday breaks the streak",
+			work: map[int]time.Duration{0: hour, 2: hour, 3: hour},
+			want: StreakStats{Current: 1, Best: 2, CurrentWeeks: 2, BestWeeks: 2},
+		},
+		{
+			// saturday 14 and sunday 15 are rest days
+			name:  "rest days are skipped",
+			work:  map[int]time.Duration{0: hour, 1: hour, 2: hour, 5: hour},
+			rules: config.Streak{RestDays: []string{"saturday", "Sunday"}},
+			want:  StreakStats{Current: 4, Best: 4, CurrentWeeks: 2, BestWeeks: 2},
+		},
+		{
+			name:  "freezes cover missed days",
+			work:  map[int]time.Duration{0: hour, 2: hour, 4: hour, 5: hour},
+			rules: config.Streak{FreezesPerMonth: 2},
+			want:  StreakStats{Current: 4, Best: 4, CurrentWeeks: 2, BestWeeks: 2, FreezesPerMonth: 2},
+		},
+		{
+			name:  "freezes run out",
+			work:  map[int]time.Duration{0: hour, 2: hour, 4: hour},
+			rules: config.Streak{FreezesPerMonth: 1},
+			want:  StreakStats{Current: 1, Best: 2, CurrentWeeks: 2, BestWeeks: 2, FreezesLeft: 0, FreezesPerMonth: 1},
+		},
+		{
+			name:  "days below the minimum don't count",
+			work:  map[int]time.Duration{0: hour, 1: 10 * time.Minute, 2: hour},
+			rules: config.Streak{MinDuration: 25 * time.Minute},
+			want:  StreakStats{Current: 1, Best: 1, CurrentWeeks: 1, BestWeeks: 1},
+		},
+		{
+			// the week of feb 2 has one counted day
+			name:  "weekly streak needs enough days",
+			work:  map[int]time.Duration{0: hour, 2: hour, 7: hour, 8: hour, 16: hour},
+			rules: config.Streak{WeeklyMinDays: 2},
+			want:  StreakStats{Current: 1, Best: 2, CurrentWeeks: 2, BestWeeks: 2},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := buildStreakStats(today, tt.work)
+			if tt.work == nil {
+				stats = nil
+			}
+
+			if got := calculateStreak(stats, today, Calendar{Location: time.UTC}, tt.rules); got != tt.want {
+				t.Fatalf("calculateStreak() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
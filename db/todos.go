@@ -0,0 +1,130 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TodoRepo struct {
+	db *sqlx.DB
+}
+
+func NewTodoRepo(db *sqlx.DB) *TodoRepo {
+	return &TodoRepo{db: db}
+}
+
+// todoRow is a todo as stored, with the work spent on it
+type todoRow struct {
+	ID           int64         `db:"id"`
+	Title        string        `db:"title"`
+	Estimate     int           `db:"estimate"`
+	CreatedAt    int64         `db:"created_at"`
+	DoneAt       sql.NullInt64 `db:"done_at"`
+	Pomodoros    int           `db:"pomodoros"`
+	WorkDuration time.Duration `db:"work_duration"`
+}
+
+// pomodoros are work sessions that ran to the end
+const selectTodos = `
+	SELECT
+		t.id, t.title, t.estimate, t.created_at, t.done_at,
+		COUNT(s.id) AS pomodoros,
+		COALESCE(SUM(s.duration), 0) AS work_duration
+	FROM todos t
+	LEFT JOIN sessions s
+		ON s.todo_id = t.id AND s.type = 'work' AND s.outcome IN ('completed', 'extended')
+`
+
+// AddTodo adds an open todo and returns its id.
+// estimate is the expected number of pomodoros, 0 if not estimated.
+func (r *TodoRepo) AddTodo(title string, estimate int) (int64, error) {
+	result, err := r.db.Exec(
+		"INSERT INTO todos (title, estimate, created_at) VALUES (?, ?, ?);",
+		title,
+		estimate,
+		time.Now().Unix(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// GetTodo retrieves the todo with the given id.
+func (r *TodoRepo) GetTodo(id int64) (Todo, error) {
+	var row todoRow
+
+	if err := r.db.Get(&row, selectTodos+"WHERE t.id = ? GROUP BY t.id;", id); err != nil {
+		return Todo{}, err
+	}
+
+	return row.todo(), nil
+}
+
+// ListTodos retrieves the open todos in the order they were added,
+// followed by the done ones if includeDone is set.
+func (r *TodoRepo) ListTodos(includeDone bool) ([]Todo, error) {
+	condition := "WHERE t.done_at IS NULL"
+	if includeDone {
+		condition = ""
+	}
+
+	var rows []todoRow
+	if err := r.db.Select(
+		&rows,
+		selectTodos+condition+" GROUP BY t.id ORDER BY t.done_at IS NOT NULL, t.id;",
+	); err != nil {
+		return nil, err
+	}
+
+	todos := make([]Todo, 0, len(rows))
+	for _, row := range rows {
+		todos = append(todos, row.todo())
+	}
+
+	return todos, nil
+}
+
+// CompleteTodo marks the todo with the given id as done.
+func (r *TodoRepo) CompleteTodo(id int64) error {
+	result, err := r.db.Exec(
+		"UPDATE todos SET done_at = ? WHERE id = ? AND done_at IS NULL;",
+		time.Now().Unix(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (row todoRow) todo() Todo {
+	todo := Todo{
+		ID:           row.ID,
+		Title:        row.Title,
+		Estimate:     row.Estimate,
+		Pomodoros:    row.Pomodoros,
+		WorkDuration: row.WorkDuration,
+		CreatedAt:    time.Unix(row.CreatedAt, 0),
+	}
+
+	if row.DoneAt.Valid {
+		doneAt := time.Unix(row.DoneAt.Int64, 0)
+		todo.DoneAt = &doneAt
+	}
+
+	return todo
+}
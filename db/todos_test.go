@@ -0,0 +1,66 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestTodoRepo(t *testing.T) {
+	sessions := newTestRepo(t)
+	todos := NewTodoRepo(sessions.db)
+
+	parserID, err := todos.AddTodo("write parser", 3)
+	if err != nil {
+		t.Fatalf("add todo: %v", err)
+	}
+	docsID, err := todos.AddTodo("update docs", 0)
+	if err != nil {
+		t.Fatalf("add todo: %v", err)
+	}
+
+	start := time.Date(2026, 2, 13, 9, 0, 0, 0, time.UTC)
+	for _, session := range []Session{
+		{StartedAt: start, Duration: 25 * time.Minute, Outcome: string(CompletedOutcome), TodoID: parserID},
+		{StartedAt: start.Add(time.Hour), Duration: 27 * time.Minute, Outcome: string(ExtendedOutcome), TodoID: parserID},
+		// abandoned sessions don't count as pomodoros
+		{StartedAt: start.Add(2 * time.Hour), Duration: 10 * time.Minute, Outcome: string(QuitOutcome), TodoID: parserID},
+		{StartedAt: start.Add(3 * time.Hour), Duration: 25 * time.Minute, Outcome: string(CompletedOutcome)},
+	} {
+		session.Type = string(WorkSession)
+		if _, err := sessions.InsertSession(session); err != nil {
+			t.Fatalf("insert session: %v", err)
+		}
+	}
+
+	parser, err := todos.GetTodo(parserID)
+	if err != nil {
+		t.Fatalf("get todo: %v", err)
+	}
+	if parser.Pomodoros != 2 || parser.WorkDuration != 52*time.Minute || parser.Estimate != 3 {
+		t.Fatalf("todo = %+v, want 2 pomodoros of 52m estimated at 3", parser)
+	}
+
+	if err := todos.CompleteTodo(parserID); err != nil {
+		t.Fatalf("complete todo: %v", err)
+	}
+	if err := todos.CompleteTodo(parserID); err != sql.ErrNoRows {
+		t.Fatalf("complete done todo error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	open, err := todos.ListTodos(false)
+	if err != nil {
+		t.Fatalf("list todos: %v", err)
+	}
+	if len(open) != 1 || open[0].ID != docsID || open[0].Pomodoros != 0 {
+		t.Fatalf("open todos = %+v, want only the docs todo", open)
+	}
+
+	all, err := todos.ListTodos(true)
+	if err != nil {
+		t.Fatalf("list todos: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != docsID || !all[1].Done() {
+		t.Fatalf("all todos = %+v, want the open todo first and the done one last", all)
+	}
+}
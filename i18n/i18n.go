@@ -0,0 +1,249 @@
+// Package i18n translates user-visible strings and formats dates
+// in the language and conventions of the configured locale.
+//
+// Messages are looked up by their English text, untranslated messages are shown in English.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// Auto detects the language from the LC_ALL, LC_MESSAGES and LANG environment variables
+	Auto    = "auto"
+	English = "en"
+)
+
+//go:embed locales/*.yaml
+var catalogFiles embed.FS
+
+// regions whose weeks start on sunday, weeks start on monday elsewhere
+var sundayRegions = []string{"US", "CA", "MX", "BR", "JP", "KR", "IL", "PH", "IN", "ZA"}
+
+// Locale holds the messages and calendar conventions of a language.
+type Locale struct {
+	// Language is the language messages are translated to, e.g. de
+	Language string
+
+	// WeekStart is the first day of the week
+	WeekStart time.Weekday
+
+	catalog catalog
+}
+
+// catalog is the content of a locale file,
+// names are indexed like time.Month - 1 and time.Weekday
+type catalog struct {
+	Name          string            `yaml:"name"`
+	WeekStart     string            `yaml:"weekStart"`
+	Months        []string          `yaml:"months"`
+	ShortMonths   []string          `yaml:"shortMonths"`
+	Weekdays      []string          `yaml:"weekdays"`
+	ShortWeekdays []string          `yaml:"shortWeekdays"`
+	Dates         map[string]string `yaml:"dates"`
+	Messages      map[string]string `yaml:"messages"`
+}
+
+var current atomic.Pointer[Locale]
+
+func init() {
+	current.Store(&Locale{Language: English, WeekStart: time.Sunday})
+}
+
+// Languages returns the supported languages, e.g. en and de.
+func Languages() []string {
+	languages := []string{English}
+
+	entries, _ := catalogFiles.ReadDir("locales")
+	for _, entry := range entries {
+		languages = append(languages, strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+	}
+
+	sort.Strings(languages)
+	return languages
+}
+
+// IsSupported reports whether language can be loaded, including [Auto].
+func IsSupported(language string) bool {
+	return language == Auto || slices.Contains(Languages(), language)
+}
+
+// Load returns the locale of language, [Auto] detects it from the environment
+// and falls back to English if the detected language is not supported.
+func Load(language string) (*Locale, error) {
+	region := ""
+	if language == Auto {
+		language, region = detect()
+		if !IsSupported(language) {
+			language = English
+		}
+	}
+
+	locale := &Locale{Language: language, WeekStart: time.Sunday}
+
+	if language != English {
+		content, err := catalogFiles.ReadFile("locales/" + language + ".yaml")
+		if err != nil {
+			return nil, fmt.Errorf("unsupported language %q, expected one of %s", language, strings.Join(Languages(), ", "))
+		}
+
+		if err := yaml.Unmarshal(content, &locale.catalog); err != nil {
+			return nil, fmt.Errorf("invalid catalog of %q: %w", language, err)
+		}
+
+		if strings.EqualFold(locale.catalog.WeekStart, time.Monday.String()) {
+			locale.WeekStart = time.Monday
+		}
+	}
+
+	// the region decides over the language, e.g. en_GB starts on monday
+	if region != "" {
+		locale.WeekStart = time.Monday
+		if slices.Contains(sundayRegions, region) {
+			locale.WeekStart = time.Sunday
+		}
+	}
+
+	return locale, nil
+}
+
+// returns the language and region of the environment, e.g. de and AT for de_AT.UTF-8
+func detect() (language, region string) {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		// e.g. de_AT.UTF-8@euro
+		value, _, _ = strings.Cut(value, ".")
+		value, _, _ = strings.Cut(value, "@")
+		if value == "C" || value == "POSIX" {
+			return English, ""
+		}
+
+		language, region, _ = strings.Cut(strings.ReplaceAll(value, "-", "_"), "_")
+		return strings.ToLower(language), strings.ToUpper(region)
+	}
+
+	return English, ""
+}
+
+// Use makes locale the current locale.
+func Use(locale *Locale) {
+	current.Store(locale)
+}
+
+// Current returns the current locale.
+func Current() *Locale {
+	return current.Load()
+}
+
+// T translates message to the current language.
+func T(message string) string {
+	return Current().T(message)
+}
+
+// Tf translates format to the current language and formats it with args.
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Plural translates one if n is 1 and other otherwise.
+func Plural(n int, one, other string) string {
+	if n == 1 {
+		return T(one)
+	}
+
+	return T(other)
+}
+
+// FormatDate formats t with an English layout of the time package,
+// using the layout and names of the current locale.
+func FormatDate(t time.Time, layout string) string {
+	return Current().FormatDate(t, layout)
+}
+
+// ShortWeekday returns the abbreviated name of day, at most 3 characters long.
+func ShortWeekday(day time.Weekday) string {
+	return Current().ShortWeekday(day)
+}
+
+// ShortMonth returns the abbreviated name of month, at most 3 characters long.
+func ShortMonth(month time.Month) string {
+	return Current().ShortMonth(month)
+}
+
+// WeekStart returns the first day of the week of the current locale.
+func WeekStart() time.Weekday {
+	return Current().WeekStart
+}
+
+// T translates message, or returns it unchanged if it has no translation.
+func (l *Locale) T(message string) string {
+	if translation, ok := l.catalog.Messages[message]; ok && translation != "" {
+		return translation
+	}
+
+	return message
+}
+
+// ShortWeekday returns the abbreviated name of day.
+func (l *Locale) ShortWeekday(day time.Weekday) string {
+	return name(l.catalog.ShortWeekdays, int(day), day.String()[:3])
+}
+
+// ShortMonth returns the abbreviated name of month.
+func (l *Locale) ShortMonth(month time.Month) string {
+	return name(l.catalog.ShortMonths, int(month)-1, month.String()[:3])
+}
+
+// placeholders for the names in layouts, the time package leaves them as they are
+const (
+	longMonthPlaceholder    = "\x01"
+	shortMonthPlaceholder   = "\x02"
+	longWeekdayPlaceholder  = "\x03"
+	shortWeekdayPlaceholder = "\x04"
+)
+
+// FormatDate formats t with an English layout of the time package,
+// the locale may translate the layout, e.g. "Jan 2" to "2. Jan".
+func (l *Locale) FormatDate(t time.Time, layout string) string {
+	if localized, ok := l.catalog.Dates[layout]; ok && localized != "" {
+		layout = localized
+	}
+
+	// translated names may contain layout elements themselves, e.g. "Januar"
+	layout = strings.NewReplacer(
+		"January", longMonthPlaceholder,
+		"Jan", shortMonthPlaceholder,
+		"Monday", longWeekdayPlaceholder,
+		"Mon", shortWeekdayPlaceholder,
+	).Replace(layout)
+
+	return strings.NewReplacer(
+		longMonthPlaceholder, name(l.catalog.Months, int(t.Month())-1, t.Month().String()),
+		shortMonthPlaceholder, l.ShortMonth(t.Month()),
+		longWeekdayPlaceholder, name(l.catalog.Weekdays, int(t.Weekday()), t.Weekday().String()),
+		shortWeekdayPlaceholder, l.ShortWeekday(t.Weekday()),
+	).Replace(t.Format(layout))
+}
+
+// returns names[i], or fallback if the catalog has no such name
+func name(names []string, i int, fallback string) string {
+	if i >= 0 && i < len(names) && names[i] != "" {
+		return names[i]
+	}
+
+	return fallback
+}
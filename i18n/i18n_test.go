@@ -0,0 +1,155 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name         string
+		language     string
+		env          map[string]string
+		wantLanguage string
+		wantStart    time.Weekday
+	}{
+		{name: "english", language: English, wantLanguage: English, wantStart: time.Sunday},
+		{name: "german", language: "de", wantLanguage: "de", wantStart: time.Monday},
+		{name: "detected", language: Auto, env: map[string]string{"LANG": "es_ES.UTF-8"}, wantLanguage: "es", wantStart: time.Monday},
+		{name: "LC_ALL first", language: Auto, env: map[string]string{"LC_ALL": "de_DE", "LANG": "es_ES"}, wantLanguage: "de", wantStart: time.Monday},
+		{name: "region starts on sunday", language: Auto, env: map[string]string{"LANG": "es_MX.UTF-8"}, wantLanguage: "es", wantStart: time.Sunday},
+		{name: "region starts on monday", language: Auto, env: map[string]string{"LANG": "en_GB.UTF-8"}, wantLanguage: English, wantStart: time.Monday},
+		{name: "unsupported falls back", language: Auto, env: map[string]string{"LANG": "fr_FR.UTF-8"}, wantLanguage: English, wantStart: time.Monday},
+		{name: "posix", language: Auto, env: map[string]string{"LANG": "C.UTF-8"}, wantLanguage: English, wantStart: time.Sunday},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			locale, err := Load(tt.language)
+			if err != nil {
+				t.Fatalf("Load(%q) error = %v", tt.language, err)
+			}
+
+			if locale.Language != tt.wantLanguage || locale.WeekStart != tt.wantStart {
+				t.Fatalf("Load(%q) = %s starting on %s, want %s starting on %s",
+					tt.language, locale.Language, locale.WeekStart, tt.wantLanguage, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestLoad_Unsupported(t *testing.T) {
+	if _, err := Load("xx"); err == nil {
+		t.Fatalf("Load(%q) error = nil, want an error", "xx")
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		language string
+		layout   string
+		want     string
+	}{
+		{language: English, layout: "Mon, Jan 2 2006", want: "Mon, Mar 3 2025"},
+		{language: "de", layout: "Mon, Jan 2 2006", want: "Mo, 3. Mär 2025"},
+		{language: "de", layout: "January 2006", want: "März 2025"},
+		{language: "de", layout: "Jan 2, 2006", want: "3. Mär 2025"},
+		{language: "es", layout: "January 2006", want: "marzo 2025"},
+		{language: "es", layout: "Jan 2", want: "3 mar"},
+		// layouts without a translation keep their order
+		{language: "de", layout: "Monday 2006-01-02", want: "Montag 2025-03-03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language+" "+tt.layout, func(t *testing.T) {
+			locale, err := Load(tt.language)
+			if err != nil {
+				t.Fatalf("Load(%q) error = %v", tt.language, err)
+			}
+
+			if got := locale.FormatDate(date, tt.layout); got != tt.want {
+				t.Fatalf("FormatDate(%q) = %q, want %q", tt.layout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	locale, err := Load("de")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got := locale.T("config reloaded"); got != "Konfiguration neu geladen" {
+		t.Fatalf("T() = %q, want the German translation", got)
+	}
+
+	if got := locale.T("my custom task"); got != "my custom task" {
+		t.Fatalf("T() = %q, want untranslated messages unchanged", got)
+	}
+}
+
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// every catalog should translate the same messages with the same format verbs
+func TestCatalogs(t *testing.T) {
+	reference, err := Load("de")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for _, language := range Languages() {
+		if language == English {
+			continue
+		}
+
+		t.Run(language, func(t *testing.T) {
+			locale, err := Load(language)
+			if err != nil {
+				t.Fatalf("Load(%q) error = %v", language, err)
+			}
+
+			c := locale.catalog
+			if len(c.Months) != 12 || len(c.ShortMonths) != 12 || len(c.Weekdays) != 7 || len(c.ShortWeekdays) != 7 {
+				t.Fatalf("catalog has %d months, %d short months, %d weekdays and %d short weekdays",
+					len(c.Months), len(c.ShortMonths), len(c.Weekdays), len(c.ShortWeekdays))
+			}
+
+			for _, short := range append(c.ShortMonths, c.ShortWeekdays...) {
+				if len([]rune(short)) > 3 {
+					t.Fatalf("short name %q is longer than 3 characters", short)
+				}
+			}
+
+			for message := range reference.catalog.Messages {
+				if _, ok := c.Messages[message]; !ok {
+					t.Fatalf("message %q is not translated", message)
+				}
+			}
+
+			for message, translation := range c.Messages {
+				if _, ok := reference.catalog.Messages[message]; !ok {
+					t.Fatalf("message %q is missing in the reference catalog", message)
+				}
+
+				want := verbPattern.FindAllString(message, -1)
+				got := verbPattern.FindAllString(translation, -1)
+				if len(got) != len(want) {
+					t.Fatalf("translation %q of %q has verbs %v, want %v", translation, message, got, want)
+				}
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("translation %q of %q has verbs %v, want %v", translation, message, got, want)
+					}
+				}
+			}
+		})
+	}
+}
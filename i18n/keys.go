@@ -0,0 +1,40 @@
+package i18n
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap translates the help texts of the bindings of a key map when the help is rendered,
+// so key maps can be declared before the language is known.
+type KeyMap struct {
+	help.KeyMap
+}
+
+func (k KeyMap) ShortHelp() []key.Binding {
+	return Bindings(k.KeyMap.ShortHelp())
+}
+
+func (k KeyMap) FullHelp() [][]key.Binding {
+	groups := k.KeyMap.FullHelp()
+
+	translated := make([][]key.Binding, len(groups))
+	for i, group := range groups {
+		translated[i] = Bindings(group)
+	}
+
+	return translated
+}
+
+// Bindings returns copies of bindings with translated help texts.
+func Bindings(bindings []key.Binding) []key.Binding {
+	translated := make([]key.Binding, len(bindings))
+
+	for i, binding := range bindings {
+		help := binding.Help()
+		binding.SetHelp(help.Key, T(help.Desc))
+		translated[i] = binding
+	}
+
+	return translated
+}
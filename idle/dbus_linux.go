@@ -0,0 +1,90 @@
+package idle
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// DBus asks the desktop screen saver for the idle time,
+// or logind if the screen saver doesn't provide it, e.g. on Wayland compositors without one.
+type DBus struct {
+	conn  *dbus.Conn
+	query func() (time.Duration, error)
+}
+
+// NewDBus connects to the first D-Bus service that reports the idle time.
+func NewDBus() (Detector, error) {
+	screenSaver, screenSaverErr := connect(dbus.ConnectSessionBus, screenSaverIdleTime)
+	if screenSaverErr == nil {
+		return screenSaver, nil
+	}
+
+	logind, logindErr := connect(dbus.ConnectSystemBus, logindIdleTime)
+	if logindErr == nil {
+		return logind, nil
+	}
+
+	return nil, fmt.Errorf("%w: %w", ErrUnsupported, errors.Join(screenSaverErr, logindErr))
+}
+
+// connects to a bus and checks that the service answers
+func connect(
+	connectBus func(...dbus.ConnOption) (*dbus.Conn, error),
+	newQuery func(*dbus.Conn) func() (time.Duration, error),
+) (*DBus, error) {
+	conn, err := connectBus()
+	if err != nil {
+		return nil, err
+	}
+
+	detector := &DBus{conn: conn, query: newQuery(conn)}
+	if _, err := detector.IdleTime(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return detector, nil
+}
+
+func (d *DBus) IdleTime() (time.Duration, error) {
+	return d.query()
+}
+
+func (d *DBus) Close() error {
+	return d.conn.Close()
+}
+
+// idle time of the org.freedesktop.ScreenSaver service, e.g. KDE and Xfce
+func screenSaverIdleTime(conn *dbus.Conn) func() (time.Duration, error) {
+	screenSaver := conn.Object("org.freedesktop.ScreenSaver", "/org/freedesktop/ScreenSaver")
+
+	return func() (time.Duration, error) {
+		var milliseconds uint32
+		err := screenSaver.Call("org.freedesktop.ScreenSaver.GetSessionIdleTime", 0).Store(&milliseconds)
+
+		return time.Duration(milliseconds) * time.Millisecond, err
+	}
+}
+
+// idle hint of the logind session pomo runs in
+func logindIdleTime(conn *dbus.Conn) func() (time.Duration, error) {
+	session := conn.Object("org.freedesktop.login1", "/org/freedesktop/login1/session/auto")
+
+	return func() (time.Duration, error) {
+		var idle bool
+		if err := session.StoreProperty("org.freedesktop.login1.Session.IdleHint", &idle); err != nil || !idle {
+			return 0, err
+		}
+
+		// microseconds since the epoch
+		var since uint64
+		if err := session.StoreProperty("org.freedesktop.login1.Session.IdleSinceHint", &since); err != nil {
+			return 0, err
+		}
+
+		return time.Since(time.UnixMicro(int64(since))), nil
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package idle
+
+// NewDBus is only available on Linux.
+func NewDBus() (Detector, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,38 @@
+package idle
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake reports the idle time it was set to, for tests.
+type Fake struct {
+	mu       sync.Mutex
+	idleTime time.Duration
+	err      error
+}
+
+func (f *Fake) SetIdleTime(idleTime time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.idleTime = idleTime
+}
+
+func (f *Fake) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.err = err
+}
+
+func (f *Fake) IdleTime() (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.idleTime, f.err
+}
+
+func (f *Fake) Close() error {
+	return nil
+}
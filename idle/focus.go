@@ -0,0 +1,50 @@
+package idle
+
+import (
+	"sync"
+	"time"
+)
+
+// Focus treats the time since the terminal lost focus as idle time.
+// It relies on the terminal reporting focus changes, see [Focus.Focused] and [Focus.Blurred].
+type Focus struct {
+	mu        sync.Mutex
+	blurredAt time.Time // zero while focused
+}
+
+func NewFocus() *Focus {
+	return &Focus{}
+}
+
+// Focused records that the terminal gained focus.
+func (f *Focus) Focused() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.blurredAt = time.Time{}
+}
+
+// Blurred records that the terminal lost focus.
+func (f *Focus) Blurred() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.blurredAt.IsZero() {
+		f.blurredAt = time.Now()
+	}
+}
+
+func (f *Focus) IdleTime() (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.blurredAt.IsZero() {
+		return 0, nil
+	}
+
+	return time.Since(f.blurredAt), nil
+}
+
+func (f *Focus) Close() error {
+	return nil
+}
@@ -0,0 +1,30 @@
+package idle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFocus(t *testing.T) {
+	focus := NewFocus()
+
+	if idleTime, _ := focus.IdleTime(); idleTime != 0 {
+		t.Fatalf("idle time while focused = %v, want 0", idleTime)
+	}
+
+	focus.Blurred()
+	focus.blurredAt = focus.blurredAt.Add(-time.Minute)
+
+	// blurring again keeps the first blur
+	focus.Blurred()
+
+	if idleTime, _ := focus.IdleTime(); idleTime < time.Minute {
+		t.Fatalf("idle time since blurred = %v, want at least 1m", idleTime)
+	}
+
+	focus.Focused()
+
+	if idleTime, _ := focus.IdleTime(); idleTime != 0 {
+		t.Fatalf("idle time after focusing = %v, want 0", idleTime)
+	}
+}
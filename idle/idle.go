@@ -0,0 +1,40 @@
+// Package idle detects how long the user has been away from the computer.
+package idle
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+// ErrUnsupported is returned by backends that are not available on this system.
+var ErrUnsupported = errors.New("idle detection is not supported on this system")
+
+// Detector reports how long there was no user input.
+type Detector interface {
+	IdleTime() (time.Duration, error)
+	Close() error
+}
+
+// New returns a detector for the given backend, one of the config.Idle* values.
+func New(backend string) (Detector, error) {
+	switch backend {
+	case config.IdleDBus:
+		return NewDBus()
+
+	case config.IdleFocus:
+		return NewFocus(), nil
+
+	default:
+		// terminal focus is only used when asked for, most terminals don't report it
+		detector, err := NewDBus()
+		if err != nil {
+			log.Println("D-Bus idle detection unavailable, set idle.backend to focus to use terminal focus:", err)
+			return nil, err
+		}
+
+		return detector, nil
+	}
+}
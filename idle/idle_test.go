@@ -0,0 +1,30 @@
+package idle
+
+import (
+	"testing"
+
+	"github.com/Bahaaio/pomo/config"
+)
+
+func TestNew_Focus(t *testing.T) {
+	detector, err := New(config.IdleFocus)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, ok := detector.(*Focus); !ok {
+		t.Fatalf("New() = %T, want *Focus", detector)
+	}
+}
+
+func TestNew_AutoNeverUsesFocus(t *testing.T) {
+	detector, err := New(config.IdleAuto)
+	if err != nil {
+		return
+	}
+	defer detector.Close()
+
+	if _, ok := detector.(*Focus); ok {
+		t.Fatalf("New() fell back to terminal focus")
+	}
+}
@@ -0,0 +1,78 @@
+// Package paths resolves where pomo stores its config, state and data,
+// following the XDG Base Directory specification on Linux and macOS.
+package paths
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const AppName = "pomo"
+
+// ConfigDir returns the directory for the config file,
+// $XDG_CONFIG_HOME/pomo or ~/.config/pomo on Linux and macOS.
+func ConfigDir() (string, error) {
+	return appDir("XDG_CONFIG_HOME", ".config")
+}
+
+// StateDir returns the directory for state that is not worth backing up such as the debug log,
+// $XDG_STATE_HOME/pomo or ~/.local/state/pomo on Linux and macOS.
+func StateDir() (string, error) {
+	return appDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// DataDir returns the directory for user data such as the session database,
+// $XDG_DATA_HOME/pomo or ~/.local/share/pomo on Linux and macOS.
+func DataDir() (string, error) {
+	return appDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// LegacyConfigDir returns the config directory used before XDG variables were honored.
+func LegacyConfigDir() (string, error) {
+	return legacyDir(".config")
+}
+
+// LegacyDataDir returns the database directory used before XDG variables were honored.
+func LegacyDataDir() (string, error) {
+	return legacyDir(filepath.Join(".local", "state"))
+}
+
+// returns the app directory inside the base directory from the given XDG variable,
+// falling back to the home relative default
+func appDir(xdgEnv, homeDefault string) (string, error) {
+	// on other OSes, use the standard user config directory
+	if !usesXDG() {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Join(dir, AppName), nil
+	}
+
+	// relative paths are invalid according to the spec and must be ignored
+	if dir := os.Getenv(xdgEnv); filepath.IsAbs(dir) {
+		return filepath.Join(dir, AppName), nil
+	}
+
+	return legacyDir(homeDefault)
+}
+
+func legacyDir(homeRelative string) (string, error) {
+	if !usesXDG() {
+		return appDir("", "")
+	}
+
+	home := os.Getenv("HOME")
+	if home == "" {
+		return "", errors.New("$HOME is not defined")
+	}
+
+	return filepath.Join(home, homeRelative, AppName), nil
+}
+
+func usesXDG() bool {
+	return runtime.GOOS == "linux" || runtime.GOOS == "darwin"
+}
@@ -0,0 +1,75 @@
+package paths
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirs(t *testing.T) {
+	if !usesXDG() {
+		t.Skip("XDG directories are only used on Linux and macOS, not on " + runtime.GOOS)
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	testCases := []struct {
+		name    string
+		dir     func() (string, error)
+		env     string
+		xdgHome string
+		want    string
+	}{
+		{"config default", ConfigDir, "XDG_CONFIG_HOME", "", filepath.Join(home, ".config", AppName)},
+		{"config from XDG", ConfigDir, "XDG_CONFIG_HOME", "/xdg/config", "/xdg/config/" + AppName},
+		{"config ignores relative XDG", ConfigDir, "XDG_CONFIG_HOME", "relative", filepath.Join(home, ".config", AppName)},
+		{"state default", StateDir, "XDG_STATE_HOME", "", filepath.Join(home, ".local", "state", AppName)},
+		{"state from XDG", StateDir, "XDG_STATE_HOME", "/xdg/state", "/xdg/state/" + AppName},
+		{"data default", DataDir, "XDG_DATA_HOME", "", filepath.Join(home, ".local", "share", AppName)},
+		{"data from XDG", DataDir, "XDG_DATA_HOME", "/xdg/data", "/xdg/data/" + AppName},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.env, tt.xdgHome)
+
+			got, err := tt.dir()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLegacyDirsIgnoreXDG(t *testing.T) {
+	if !usesXDG() {
+		t.Skip("XDG directories are only used on Linux and macOS, not on " + runtime.GOOS)
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+
+	configDir, err := LegacyConfigDir()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config", AppName), configDir)
+
+	dataDir, err := LegacyDataDir()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".local", "state", AppName), dataDir)
+}
+
+func TestDirsWithoutHome(t *testing.T) {
+	if !usesXDG() {
+		t.Skip("XDG directories are only used on Linux and macOS, not on " + runtime.GOOS)
+	}
+
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+
+	_, err := DataDir()
+	assert.Error(t, err)
+}
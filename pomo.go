@@ -1,12 +1,22 @@
 package main
 
 import (
+	_ "embed"
 	"os"
 
+	// time zone data for stats.timezone on systems without a zoneinfo database
+	_ "time/tzdata"
+
 	"github.com/Bahaaio/pomo/cmd"
+	"github.com/Bahaaio/pomo/config"
 )
 
+//go:embed pomo.yaml
+var exampleConfig []byte
+
 func main() {
+	config.Example = exampleConfig
+
 	if err := cmd.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -4,7 +4,11 @@ package ui
 import (
 	"time"
 
+	"github.com/Bahaaio/pomo/i18n"
 	"github.com/Bahaaio/pomo/ui/confirm"
+	"github.com/Bahaaio/pomo/ui/interruption"
+	"github.com/Bahaaio/pomo/ui/picker"
+	"github.com/Bahaaio/pomo/ui/reflection"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/timer"
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,34 +16,98 @@ import (
 )
 
 func (m Model) Init() tea.Cmd {
-	return m.timer.Init()
+	var cmds []tea.Cmd
+
+	// the timer starts once a todo is picked
+	if m.sessionState != ShowingPicker {
+		cmds = append(cmds, m.timer.Init())
+	}
+
+	if m.idleDetector != nil {
+		cmds = append(cmds, m.checkIdleTime())
+	}
+
+	return tea.Batch(cmds...)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmd := m.update(msg)
+
+	// the title is set before quitting so it can be reset
+	if titleCmd := m.updateWindowTitle(); titleCmd != nil {
+		return m, tea.Sequence(titleCmd, cmd)
+	}
+
+	return m, cmd
+}
+
+func (m *Model) update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		return m, m.handleKeys(msg)
+		return m.handleKeys(msg)
 
 	case tea.WindowSizeMsg:
-		return m, m.handleWindowResize(msg)
+		return m.handleWindowResize(msg)
 
 	case timer.TickMsg:
-		return m, m.handleTimerTick(msg)
+		return m.handleTimerTick(msg)
 
 	case confirmTickMsg:
-		return m, m.handleConfirmTick()
+		return m.handleConfirmTick()
+
+	case pauseTickMsg:
+		return m.handlePauseTick(msg)
+
+	case idleTickMsg:
+		return m.checkIdleTime()
+
+	case idleTimeMsg:
+		return m.handleIdleTime(msg)
+
+	case tea.FocusMsg, tea.BlurMsg:
+		m.handleFocusChange(msg)
+		return nil
 
 	case timer.StartStopMsg:
-		return m, m.handleTimerStartStop(msg)
+		return m.handleTimerStartStop(msg)
 
 	case confirm.ChoiceMsg:
-		return m, m.handleConfirmChoice(msg)
+		return m.handleConfirmChoice(msg)
+
+	case reflection.DoneMsg:
+		return m.handleReflectionDone(msg)
+
+	case picker.PickedMsg:
+		return m.handleTodoPicked(msg)
+
+	case interruption.LoggedMsg:
+		m.handleInterruptionLogged(msg)
+		return nil
 
 	case progress.FrameMsg:
-		return m, m.handleProgressBarFrame(msg)
+		return m.handleProgressBarFrame(msg)
+
+	case ConfigReloadedMsg:
+		return m.handleConfigReloaded(msg)
+
+	case notificationFailedMsg:
+		return m.handleNotificationFailed(msg)
+
+	case clearConfigStatusMsg:
+		m.handleClearConfigStatus(msg)
+		return nil
 
 	default:
-		return m, nil
+		// e.g. cursor blinks of the note input
+		if m.sessionState == ShowingReflection {
+			return m.reflectionPrompt.Update(msg)
+		}
+
+		if m.interruptionLog.Active() {
+			return m.interruptionLog.Update(msg)
+		}
+
+		return nil
 	}
 }
 
@@ -50,21 +118,39 @@ func (m Model) View() string {
 
 	// show confirmation dialog
 	if m.sessionState == ShowingConfirm {
-		title := m.currentTaskType.Opposite().GetTask().Title
+		title := m.currentTaskType.Opposite().GetTask().Localized().Title
 		idle := time.Since(m.confirmStartTime).Truncate(time.Second)
 
-		return m.confirmDialog.View("start "+title+"?", time.Duration(idle))
+		return m.confirmDialog.View(i18n.Tf("start %s?", title), time.Duration(idle))
+	}
+
+	// show reflection prompt
+	if m.sessionState == ShowingReflection {
+		return m.reflectionPrompt.View(i18n.Tf("%s done, how did it go?", m.currentTask.Title))
+	}
+
+	// show todo picker
+	if m.sessionState == ShowingPicker {
+		return m.todoPicker.View(i18n.Tf("what is this %s for?", m.currentTask.Title))
 	}
 
 	content := m.buildMainContent()
 	content += m.buildStatusIndicators()
+	content += m.buildTodo()
+	content += m.buildPausedTime()
 	content += m.buildProgressBar()
 
+	// the interruption input replaces the help while the timer keeps running
 	help := m.buildHelpView()
+	if m.interruptionLog.Active() {
+		help = m.interruptionLog.View()
+	} else if m.idleReturned {
+		help = m.buildIdlePrompt()
+	}
 
 	return lipgloss.Place(
 		m.width, m.height,
 		lipgloss.Center, lipgloss.Center,
-		lipgloss.JoinVertical(lipgloss.Center, content, help),
+		lipgloss.JoinVertical(lipgloss.Center, content, help, m.buildConfigStatus()),
 	)
 }
@@ -24,6 +24,12 @@ func GetFont(fontName string) Font {
 	return fonts[DefaultFont]
 }
 
+// FontExists reports whether a font with the given name exists.
+func FontExists(fontName string) bool {
+	_, exists := fonts[fontName]
+	return exists
+}
+
 func renderDigit(digit rune, font Font) string {
 	if digit == ':' {
 		return font[len(font)-1]
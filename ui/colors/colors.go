@@ -24,6 +24,9 @@ const (
 	NoColor     = lipgloss.Color("default")
 )
 
+// None disables coloring when used as a configured color.
+const None = "none"
+
 const (
 	// Timer & primary UI
 	TimerFg  = Purple
@@ -74,3 +77,13 @@ func GetColor(color string) lipgloss.TerminalColor {
 	log.Println("using color:", color)
 	return lipgloss.Color(color)
 }
+
+// IsValid reports whether color is a valid configured color,
+// either a hex color code or [None].
+func IsValid(color string) bool {
+	if color == None {
+		return true
+	}
+
+	return validColorRegex != nil && validColorRegex.MatchString(color)
+}
@@ -40,3 +40,10 @@ func TestValidHexColor(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValid(t *testing.T) {
+	assert.True(t, colors.IsValid("#FF5733"))
+	assert.True(t, colors.IsValid("none"))
+	assert.False(t, colors.IsValid("red"))
+	assert.False(t, colors.IsValid(""))
+}
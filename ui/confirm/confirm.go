@@ -4,6 +4,7 @@ package confirm
 import (
 	"time"
 
+	"github.com/Bahaaio/pomo/i18n"
 	"github.com/Bahaaio/pomo/ui/colors"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -85,11 +86,11 @@ func (m Model) View(prompt string, idleDuration time.Duration) string {
 	var confirmButton, cancelButton string
 
 	if m.confirmed {
-		confirmButton = activeButtonStyle.Render(confirmText)
-		cancelButton = InactiveButtonStyle.Render(cancelText)
+		confirmButton = activeButtonStyle.Render(i18n.T(confirmText))
+		cancelButton = InactiveButtonStyle.Render(i18n.T(cancelText))
 	} else {
-		confirmButton = InactiveButtonStyle.Render(confirmText)
-		cancelButton = activeButtonStyle.Render(cancelText)
+		confirmButton = InactiveButtonStyle.Render(i18n.T(confirmText))
+		cancelButton = activeButtonStyle.Render(i18n.T(cancelText))
 	}
 
 	buttons := lipgloss.JoinHorizontal(lipgloss.Right, confirmButton, cancelButton)
@@ -98,10 +99,10 @@ func (m Model) View(prompt string, idleDuration time.Duration) string {
 
 	idle := ""
 	if idleDuration.Seconds() > 0 {
-		idle = idleStyle.Render("idle for " + idleDuration.String())
+		idle = idleStyle.Render(i18n.Tf("idle for %v", idleDuration))
 	}
 
-	help := m.help.View(Keys)
+	help := m.help.View(i18n.KeyMap{KeyMap: Keys})
 
 	return lipgloss.Place(
 		m.width, m.height,
@@ -4,12 +4,18 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/Bahaaio/pomo/actions"
 	"github.com/Bahaaio/pomo/config"
 	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/Bahaaio/pomo/idle"
 	"github.com/Bahaaio/pomo/ui/confirm"
+	"github.com/Bahaaio/pomo/ui/interruption"
+	"github.com/Bahaaio/pomo/ui/picker"
+	"github.com/Bahaaio/pomo/ui/reflection"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/timer"
@@ -18,28 +24,71 @@ import (
 
 type confirmTickMsg struct{}
 
+type pauseTickMsg struct {
+	id int
+}
+
+type idleTickMsg struct{}
+
+type idleTimeMsg struct {
+	idle time.Duration
+	err  error
+}
+
+// how often the idle time is checked
+const idleCheckInterval = 5 * time.Second
+
+// ConfigReloadedMsg is sent when the config file changes.
+// Err is set if the new config could not be loaded.
+type ConfigReloadedMsg struct {
+	Config config.Config
+	Err    error
+}
+
+type clearConfigStatusMsg struct {
+	id int
+}
+
+// notificationFailedMsg is sent when notifiers failed to send a notification
+type notificationFailedMsg struct {
+	err error
+}
+
+// how long the config reload and notification failure indicators are shown
+const configStatusDuration = 3 * time.Second
+
 func (m *Model) handleKeys(msg tea.KeyMsg) tea.Cmd {
 	if m.sessionState == ShowingConfirm {
 		return m.confirmDialog.HandleKeys(msg)
 	}
 
+	if m.sessionState == ShowingReflection {
+		return m.reflectionPrompt.HandleKeys(msg)
+	}
+
+	if m.sessionState == ShowingPicker {
+		return m.todoPicker.HandleKeys(msg)
+	}
+
+	// keys are typed into the interruption note, ctrl+c still quits
+	if m.interruptionLog.Active() && msg.Type != tea.KeyCtrlC {
+		return m.interruptionLog.HandleKeys(msg)
+	}
+
 	switch {
+	case m.idleReturned && key.Matches(msg, idleKeyMap.Discard):
+		return m.discardIdleTime()
+
 	case key.Matches(msg, keyMap.Increase):
 		m.duration += time.Minute
 		return m.updateProgressBar()
 
 	case key.Matches(msg, keyMap.Pause):
 		if m.sessionState == Paused {
-			m.sessionState = Running
-		} else {
-			m.sessionState = Paused
+			return m.resume()
 		}
 
-		if m.sessionState == Running {
-			return m.timer.Start()
-		}
-
-		return nil
+		return m.pause()
 
 	case key.Matches(msg, keyMap.Reset):
 		m.elapsed = 0
@@ -47,11 +96,17 @@ func (m *Model) handleKeys(msg tea.KeyMsg) tea.Cmd {
 		return m.updateProgressBar()
 
 	case key.Matches(msg, keyMap.Skip):
-		m.recordSession()
+		m.recordSession(db.SkippedOutcome)
 		return m.nextSession()
 
+	case key.Matches(msg, keyMap.Internal):
+		return m.interruptionLog.Start(db.InternalInterruption)
+
+	case key.Matches(msg, keyMap.External):
+		return m.interruptionLog.Start(db.ExternalInterruption)
+
 	case key.Matches(msg, keyMap.Quit):
-		m.recordSession()
+		m.recordSession(db.QuitOutcome)
 		return m.Quit()
 
 	default:
@@ -59,6 +114,191 @@ func (m *Model) handleKeys(msg tea.KeyMsg) tea.Cmd {
 	}
 }
 
+// pauses the session, ticks keep coming to update the paused time and enforce the max pause
+func (m *Model) pause() tea.Cmd {
+	m.sessionState = Paused
+	m.pauseStartedAt = time.Now()
+	m.pauseID++
+	m.pauseReminded = false
+
+	return pauseTick(m.pauseID)
+}
+
+func (m *Model) resume() tea.Cmd {
+	m.endPause()
+	m.clearIdle()
+	m.sessionState = Running
+
+	return m.timer.Start()
+}
+
+// records the current pause if there is one
+func (m *Model) endPause() {
+	if m.pauseStartedAt.IsZero() {
+		return
+	}
+
+	pause := db.Pause{StartedAt: m.pauseStartedAt, EndedAt: time.Now()}
+	m.pauses = append(m.pauses, pause)
+	m.sessionSummary.AddPausedDuration(pause.Duration())
+
+	m.pauseStartedAt = time.Time{}
+}
+
+// returns the paused time of the current session, including the current pause
+func (m Model) pausedDuration() time.Duration {
+	var paused time.Duration
+	for _, pause := range m.pauses {
+		paused += pause.Duration()
+	}
+
+	if !m.pauseStartedAt.IsZero() {
+		paused += time.Since(m.pauseStartedAt)
+	}
+
+	return paused
+}
+
+func pauseTick(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return pauseTickMsg{id: id}
+	})
+}
+
+// reminds or ends the session once the pause reaches its maximum length
+func (m *Model) handlePauseTick(msg pauseTickMsg) tea.Cmd {
+	// ignore ticks of previous pauses
+	if msg.id != m.pauseID || m.sessionState != Paused {
+		return nil
+	}
+
+	limit := config.C.Pause
+	if limit.Max <= 0 || m.pauseReminded || time.Since(m.pauseStartedAt) < limit.Max {
+		return pauseTick(msg.id)
+	}
+
+	if limit.OnMax == config.PauseEnd {
+		log.Println("pause reached its maximum length, ending session")
+
+		m.recordSession(db.QuitOutcome)
+		return m.Quit()
+	}
+
+	log.Println("pause reached its maximum length, sending reminder")
+	m.pauseReminded = true
+
+	reminder := config.Notification{
+		Enabled: true,
+		Title:   i18n.T("still paused ⏸️"),
+		Message: i18n.Tf("%s paused for %v", m.currentTask.Title, limit.Max),
+		Icon:    m.currentTask.Notification.Icon,
+	}
+
+	notifiers, terminal := m.notifiers(), config.C.Terminal
+
+	return tea.Batch(
+		func() tea.Msg {
+			if err := actions.Notify(notifiers, terminal, reminder); err != nil {
+				return notificationFailedMsg{err: err}
+			}
+			return nil
+		},
+		pauseTick(msg.id),
+	)
+}
+
+// queries the idle time off the ui goroutine, D-Bus calls may block
+func (m *Model) checkIdleTime() tea.Cmd {
+	detector := m.idleDetector
+
+	return func() tea.Msg {
+		idleTime, err := detector.IdleTime()
+		return idleTimeMsg{idle: idleTime, err: err}
+	}
+}
+
+// pauses running work sessions when the user is away,
+// and offers to discard the time counted while away once they are back
+func (m *Model) handleIdleTime(msg idleTimeMsg) tea.Cmd {
+	next := tea.Tick(idleCheckInterval, func(time.Time) tea.Msg {
+		return idleTickMsg{}
+	})
+
+	if msg.err != nil {
+		log.Printf("failed to get idle time: %v", msg.err)
+		return next
+	}
+
+	switch {
+	case m.sessionState == Running && m.currentTaskType == config.WorkTask && msg.idle >= m.idleAfter:
+		log.Printf("idle for %v, pausing session", msg.idle)
+
+		m.idlePaused = true
+		m.awayCounted = min(msg.idle, m.elapsed)
+		return tea.Batch(m.pause(), next)
+
+	// any input since the automatic pause
+	case m.idlePaused && m.sessionState == Paused && msg.idle < time.Since(m.pauseStartedAt):
+		m.idleReturned = true
+	}
+
+	return next
+}
+
+// resumes without the idle time counted before the automatic pause,
+// it is recorded as part of the pause instead
+func (m *Model) discardIdleTime() tea.Cmd {
+	log.Printf("discarding %v of idle time", m.awayCounted)
+
+	m.elapsed -= m.awayCounted
+	m.pauseStartedAt = m.pauseStartedAt.Add(-m.awayCounted)
+
+	return tea.Batch(m.updateProgressBar(), m.resume())
+}
+
+func (m *Model) clearIdle() {
+	m.idlePaused = false
+	m.idleReturned = false
+	m.awayCounted = 0
+}
+
+// tracks the terminal focus, focusing clears the urgent indicator
+// and the focus idle detector is told about it
+func (m *Model) handleFocusChange(msg tea.Msg) {
+	_, focused := msg.(tea.FocusMsg)
+	m.unfocused = !focused
+
+	if focused {
+		m.urgent = false
+	}
+
+	focus, ok := m.idleDetector.(*idle.Focus)
+	if !ok {
+		return
+	}
+
+	if focused {
+		focus.Focused()
+	} else {
+		focus.Blurred()
+	}
+}
+
+// sets the window title if it changed, resets it when quitting
+func (m *Model) updateWindowTitle() tea.Cmd {
+	title := ""
+	if config.C.Terminal.WindowTitle && m.sessionState != Quitting {
+		title = m.buildWindowTitle()
+	}
+
+	if title == m.windowTitle {
+		return nil
+	}
+
+	m.windowTitle = title
+	return tea.SetWindowTitle(title)
+}
+
 func (m *Model) handleConfirmChoice(msg confirm.ChoiceMsg) tea.Cmd {
 	switch msg.Choice {
 	case confirm.Confirm:
@@ -72,8 +312,158 @@ func (m *Model) handleConfirmChoice(msg confirm.ChoiceMsg) tea.Cmd {
 	return nil
 }
 
+// saves the note and rating of the completed session and moves on
+func (m *Model) handleReflectionDone(msg reflection.DoneMsg) tea.Cmd {
+	if msg.Note != "" || msg.Rating > 0 {
+		m.sessionSummary.AddNote(msg.Note, msg.Rating)
+
+		if m.repo != nil && m.lastSessionID != 0 {
+			if err := m.repo.SetSessionNote(m.lastSessionID, msg.Note, msg.Rating); err != nil {
+				log.Printf("failed to save session note: %v", err)
+			}
+		}
+	}
+
+	if msg.Quit {
+		return m.Quit()
+	}
+
+	return m.continueAfterCompletion()
+}
+
+// adds an interruption to the current session, it is saved along with the session
+func (m *Model) handleInterruptionLogged(msg interruption.LoggedMsg) {
+	m.interruptions = append(m.interruptions, db.Interruption{
+		Kind:       msg.Kind,
+		Note:       msg.Note,
+		OccurredAt: time.Now(),
+	})
+	m.sessionSummary.AddInterruption(msg.Kind)
+}
+
+// starts the work session on the picked todo
+func (m *Model) handleTodoPicked(msg picker.PickedMsg) tea.Cmd {
+	if msg.Quit {
+		return m.Quit()
+	}
+
+	m.currentTodo = msg.Todo
+	m.sessionState = Running
+
+	return m.timer.Start()
+}
+
+// loads the open todos into the picker, returns false if there are none to pick from
+func (m *Model) loadTodoPicker() bool {
+	if m.todos == nil {
+		return false
+	}
+
+	todos, err := m.todos.ListTodos(false)
+	if err != nil {
+		log.Printf("failed to load todos: %v", err)
+		return false
+	}
+
+	if len(todos) == 0 {
+		m.currentTodo = nil
+		return false
+	}
+
+	var selectedID int64
+	if m.currentTodo != nil {
+		selectedID = m.currentTodo.ID
+	}
+
+	m.todoPicker.SetTodos(todos, selectedID)
+	return true
+}
+
+// applies the safe parts of a reloaded config,
+// the duration of the running session is left intact
+func (m *Model) handleConfigReloaded(msg ConfigReloadedMsg) tea.Cmd {
+	if msg.Err != nil {
+		log.Println("failed to reload config:", msg.Err)
+
+		var invalidConfig *config.InvalidConfigError
+		if errors.As(msg.Err, &invalidConfig) {
+			return m.setConfigStatus(i18n.Tf("config not reloaded: %v", invalidConfig.Problems[0]), true)
+		}
+
+		return m.setConfigStatus(i18n.Tf("config not reloaded: %v", firstLine(msg.Err.Error())), true)
+	}
+
+	log.Println("applying reloaded config")
+
+	// next sessions use the new durations and titles
+	config.C = msg.Config
+
+	m.shouldAskToContinue = msg.Config.AskToContinue
+	m.shouldAskForNotes = msg.Config.AskForNotes
+	m.applyASCIIArt(msg.Config.ASCIIArt)
+
+	if locale, err := i18n.Load(msg.Config.Language); err == nil {
+		i18n.Use(locale)
+	}
+
+	task := m.currentTaskType.GetTask().Localized()
+	m.currentTask.Notification = task.Notification
+	m.currentTask.Then = task.Then
+	m.currentTask.Notifiers = task.Notifiers
+
+	return m.setConfigStatus(i18n.T("config reloaded"), false)
+}
+
+func (m *Model) setConfigStatus(status string, isError bool) tea.Cmd {
+	m.configStatusID++
+	m.configStatus = status
+	m.configStatusError = isError
+
+	id := m.configStatusID
+	return tea.Tick(configStatusDuration, func(time.Time) tea.Msg {
+		return clearConfigStatusMsg{id: id}
+	})
+}
+
+func (m *Model) handleClearConfigStatus(msg clearConfigStatusMsg) {
+	// ignore stale messages from previous reloads
+	if msg.id == m.configStatusID {
+		m.configStatus = ""
+	}
+}
+
+// shows which notifiers failed in the status line
+func (m *Model) handleNotificationFailed(msg notificationFailedMsg) tea.Cmd {
+	var notifierErr *actions.NotifierError
+
+	failed := []error{msg.err}
+	if joined, ok := msg.err.(interface{ Unwrap() []error }); ok {
+		failed = joined.Unwrap()
+	}
+
+	if len(failed) == 1 {
+		return m.setConfigStatus(i18n.Tf("notification failed: %v", firstLine(failed[0].Error())), true)
+	}
+
+	types := make([]string, 0, len(failed))
+	for _, err := range failed {
+		if errors.As(err, &notifierErr) {
+			types = append(types, notifierErr.Type)
+		}
+	}
+
+	return m.setConfigStatus(i18n.Tf("%d notifiers failed: %s", len(failed), strings.Join(types, ", ")), true)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
 func (m *Model) handleWindowResize(msg tea.WindowSizeMsg) tea.Cmd {
 	m.confirmDialog.HandleWindowResize(msg) // always update it
+	m.reflectionPrompt.HandleWindowResize(msg)
+	m.todoPicker.HandleWindowResize(msg)
 
 	m.width = msg.Width
 	m.height = msg.Height
@@ -144,9 +534,54 @@ func (m Model) getPercent() float64 {
 func (m *Model) handleCompletion() tea.Cmd {
 	log.Println("timer completed")
 
-	m.recordSession()
-	actions.RunPostActions(&m.currentTask).Wait()
+	m.recordSession(db.CompletedOutcome)
+
+	postActions := m.runPostActions()
+
+	// marked until the terminal is focused again
+	m.urgent = m.unfocused
+
+	// ask what got done in work sessions, short sessions extend the previous one
+	if m.shouldAskForNotes && m.currentTaskType == config.WorkTask && !m.isShortSession {
+		m.sessionState = ShowingReflection
+		return tea.Batch(postActions, m.reflectionPrompt.Reset())
+	}
+
+	next := m.continueAfterCompletion()
+
+	// let the post actions finish before quitting
+	if m.sessionState == Quitting {
+		return tea.Sequence(postActions, next)
+	}
+
+	return tea.Batch(postActions, next)
+}
+
+// runs the post actions of the current task off the ui goroutine, commands may block
+func (m Model) runPostActions() tea.Cmd {
+	task := m.currentTask
+	notifiers, terminal := m.notifiers(), config.C.Terminal
 
+	return func() tea.Msg {
+		if err := actions.RunPostActions(&task, notifiers, terminal); err != nil {
+			return notificationFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// returns the notifiers of the current task, or those of the config if it has none,
+// read on the ui goroutine as a reloaded config replaces them there
+func (m Model) notifiers() []config.Notifier {
+	if len(m.currentTask.Notifiers) > 0 {
+		return m.currentTask.Notifiers
+	}
+
+	return config.C.Notifiers
+}
+
+// asks to continue with the next session, or quits if configured not to ask
+func (m *Model) continueAfterCompletion() tea.Cmd {
 	// show confirmation dialog if configured to do so
 	if m.shouldAskToContinue {
 		m.sessionState = ShowingConfirm
@@ -165,14 +600,14 @@ func (m *Model) handleCompletion() tea.Cmd {
 // starts session with the opposite task type (work <-> break)
 func (m *Model) nextSession() tea.Cmd {
 	nextTaskType := m.currentTaskType.Opposite()
-	return m.startSession(nextTaskType, *nextTaskType.GetTask(), false)
+	return m.startSession(nextTaskType, nextTaskType.GetTask().Localized(), false)
 }
 
 // starts a short session of the current task type
 func (m *Model) shortSession() tea.Cmd {
 	shortTask := m.currentTask
 	shortTask.Duration = 2 * time.Minute // TODO: make configurable
-	shortTask.Title = "short " + m.currentTaskType.GetTask().Title
+	shortTask.Title = i18n.Tf("short %s", m.currentTaskType.GetTask().Localized().Title)
 
 	return m.startSession(m.currentTaskType, shortTask, true)
 }
@@ -186,6 +621,13 @@ func (m *Model) startSession(taskType config.TaskType, task config.Task, isShort
 	m.elapsed = 0
 	m.duration = m.currentTask.Duration
 	m.timer = timer.New(m.currentTask.Duration)
+	m.clearIdle()
+
+	// ask for the todo before a work session, short sessions keep the current one
+	if taskType == config.WorkTask && !isShortSession && m.loadTodoPicker() {
+		m.sessionState = ShowingPicker
+		return m.progressBar.SetPercent(0.0)
+	}
 
 	m.sessionState = Running
 	return tea.Batch(
@@ -195,7 +637,15 @@ func (m *Model) startSession(taskType config.TaskType, task config.Task, isShort
 }
 
 // records the current session into the session summary
-func (m *Model) recordSession() {
+// along with how it ended
+func (m *Model) recordSession(outcome db.SessionOutcome) {
+	// keep an interruption that was still being typed
+	if m.interruptionLog.Active() {
+		m.handleInterruptionLogged(m.interruptionLog.Close())
+	}
+	m.endPause()
+	defer func() { m.interruptions, m.pauses = nil, nil }()
+
 	// ignore very short or zero duration sessions
 	if m.elapsed < time.Second {
 		return
@@ -204,27 +654,38 @@ func (m *Model) recordSession() {
 	// short sessions extend the current session without incrementing the count
 	if m.isShortSession {
 		m.sessionSummary.AddDuration(m.currentTaskType, m.elapsed)
-		m.persistShortSession()
+		m.persistShortSession(outcome)
 		return
 	}
 
 	m.sessionSummary.AddSession(m.currentTaskType, m.elapsed)
+	m.lastSessionID = 0
+	m.countTodoPomodoro(outcome)
 
 	// return if no database is configured
 	if m.repo == nil {
 		return
 	}
 
-	if err := m.repo.CreateSession(
-		calculateSessionStartTime(time.Now(), m.elapsed),
-		m.elapsed,
-		db.GetSessionType(m.currentTaskType),
-	); err != nil {
+	id, err := m.repo.InsertSession(db.Session{
+		StartedAt:       calculateSessionStartTime(time.Now(), m.elapsed),
+		Duration:        m.elapsed,
+		PlannedDuration: m.currentTask.Duration,
+		Type:            string(db.GetSessionType(m.currentTaskType)),
+		Profile:         m.profile,
+		Outcome:         string(outcome),
+		TodoID:          m.sessionTodoID(),
+	})
+	if err != nil {
 		log.Printf("failed to record session: %v", err)
+		return
 	}
+
+	m.lastSessionID = id
+	m.persistSessionEvents(id)
 }
 
-func (m *Model) persistShortSession() {
+func (m *Model) persistShortSession(outcome db.SessionOutcome) {
 	// return if no database is configured
 	if m.repo == nil {
 		return
@@ -233,7 +694,10 @@ func (m *Model) persistShortSession() {
 	sessionType := db.GetSessionType(m.currentTaskType)
 
 	// Short session extends the previous same-type session in persistent stats.
-	if err := m.repo.ExtendLatestSession(m.elapsed, sessionType); err == nil {
+	// the events belong to the extended session, another pomo may have recorded a later one
+	if id, err := m.repo.ExtendLatestSession(m.elapsed, sessionType, m.profile); err == nil {
+		m.lastSessionID = id
+		m.persistSessionEvents(id)
 		return
 	} else if !errors.Is(err, sql.ErrNoRows) {
 		log.Printf("failed to extend latest session: %v", err)
@@ -241,12 +705,55 @@ func (m *Model) persistShortSession() {
 	}
 
 	// Fallback for edge case where no previous same-type session exists.
-	if err := m.repo.CreateSession(
-		calculateSessionStartTime(time.Now(), m.elapsed),
-		m.elapsed,
-		sessionType,
-	); err != nil {
+	id, err := m.repo.InsertSession(db.Session{
+		StartedAt:       calculateSessionStartTime(time.Now(), m.elapsed),
+		Duration:        m.elapsed,
+		PlannedDuration: m.currentTask.Duration,
+		Type:            string(sessionType),
+		Profile:         m.profile,
+		Outcome:         string(outcome),
+		TodoID:          m.sessionTodoID(),
+	})
+	if err != nil {
 		log.Printf("failed to record short session: %v", err)
+		return
+	}
+
+	m.persistSessionEvents(id)
+}
+
+// saves the interruptions and pauses of the current session with the session they happened in
+func (m *Model) persistSessionEvents(sessionID int64) {
+	if sessionID == 0 {
+		return
+	}
+
+	if len(m.interruptions) > 0 {
+		if err := m.repo.InsertInterruptions(sessionID, m.interruptions); err != nil {
+			log.Printf("failed to record interruptions: %v", err)
+		}
+	}
+
+	if len(m.pauses) > 0 {
+		if err := m.repo.InsertPauses(sessionID, m.pauses); err != nil {
+			log.Printf("failed to record pauses: %v", err)
+		}
+	}
+}
+
+// returns the id of the todo the current session is spent on, 0 if none
+func (m *Model) sessionTodoID() int64 {
+	if m.currentTodo == nil || m.currentTaskType != config.WorkTask {
+		return 0
+	}
+
+	return m.currentTodo.ID
+}
+
+// counts a finished work session towards the shown pomodoros of the current todo
+func (m *Model) countTodoPomodoro(outcome db.SessionOutcome) {
+	if m.sessionTodoID() != 0 && outcome == db.CompletedOutcome {
+		m.currentTodo.Pomodoros++
 	}
 }
 
@@ -259,6 +766,12 @@ func calculateSessionStartTime(now time.Time, elapsed time.Duration) time.Time {
 }
 
 func (m *Model) Quit() tea.Cmd {
+	if m.idleDetector != nil {
+		if err := m.idleDetector.Close(); err != nil {
+			log.Printf("failed to close idle detector: %v", err)
+		}
+	}
+
 	m.sessionState = Quitting
 	return tea.Quit
 }
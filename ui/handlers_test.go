@@ -1,8 +1,20 @@
 package ui
 
 import (
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/Bahaaio/pomo/actions"
+	"github.com/Bahaaio/pomo/config"
+	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/idle"
+	"github.com/Bahaaio/pomo/ui/interruption"
+	"github.com/Bahaaio/pomo/ui/picker"
+	"github.com/Bahaaio/pomo/ui/reflection"
+	"github.com/charmbracelet/bubbles/timer"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jmoiron/sqlx"
 )
 
 func TestCalculateSessionStartTime(t *testing.T) {
@@ -26,3 +38,396 @@ func TestCalculateSessionStartTime_NonPositiveElapsed(t *testing.T) {
 	}
 }
 
+func TestHandleConfigReloaded_KeepsCurrentDuration(t *testing.T) {
+	old := config.C
+	t.Cleanup(func() { config.C = old })
+
+	m := Model{
+		currentTaskType: config.WorkTask,
+		currentTask:     config.Task{Title: "work", Duration: 25 * time.Minute},
+		duration:        25 * time.Minute,
+	}
+
+	reloaded := config.Config{
+		Work: config.Task{
+			Title:        "focus",
+			Duration:     50 * time.Minute,
+			Then:         [][]string{{"echo", "done"}},
+			Notification: config.Notification{Enabled: true, Title: "done"},
+		},
+		AskToContinue: true,
+	}
+
+	m.handleConfigReloaded(ConfigReloadedMsg{Config: reloaded})
+
+	if m.currentTask.Duration != 25*time.Minute || m.duration != 25*time.Minute {
+		t.Fatalf("current duration changed to %v", m.currentTask.Duration)
+	}
+	if m.currentTask.Title != "work" {
+		t.Fatalf("current title changed to %q", m.currentTask.Title)
+	}
+	if m.currentTask.Notification.Title != "done" || len(m.currentTask.Then) != 1 {
+		t.Fatalf("hooks were not applied: %+v", m.currentTask)
+	}
+	if config.C.Work.Duration != 50*time.Minute {
+		t.Fatalf("next session duration = %v, want %v", config.C.Work.Duration, 50*time.Minute)
+	}
+	if !m.shouldAskToContinue {
+		t.Fatalf("askToContinue was not applied")
+	}
+	if m.configStatus != "config reloaded" || m.configStatusError {
+		t.Fatalf("config status = %q (error: %v)", m.configStatus, m.configStatusError)
+	}
+}
+
+func TestHandleConfigReloaded_Error(t *testing.T) {
+	m := Model{currentTask: config.Task{Duration: 25 * time.Minute}}
+
+	m.handleConfigReloaded(ConfigReloadedMsg{Err: errors.New("bad yaml\nmore details")})
+
+	if m.configStatus != "config not reloaded: bad yaml" || !m.configStatusError {
+		t.Fatalf("config status = %q (error: %v)", m.configStatus, m.configStatusError)
+	}
+
+	// stale clear messages are ignored
+	m.handleClearConfigStatus(clearConfigStatusMsg{id: m.configStatusID - 1})
+	if m.configStatus == "" {
+		t.Fatalf("stale clear message cleared the status")
+	}
+
+	m.handleClearConfigStatus(clearConfigStatusMsg{id: m.configStatusID})
+	if m.configStatus != "" {
+		t.Fatalf("config status was not cleared")
+	}
+}
+
+func TestHandleReflectionDone_ContinuesAfterCompletion(t *testing.T) {
+	testCases := []struct {
+		name          string
+		askToContinue bool
+		msg           reflection.DoneMsg
+		want          SessionState
+	}{
+		{"asks to continue", true, reflection.DoneMsg{Note: "wrote tests", Rating: 4}, ShowingConfirm},
+		{"quits when not asking", false, reflection.DoneMsg{}, Quitting},
+		{"quits on quit", true, reflection.DoneMsg{Note: "almost done", Quit: true}, Quitting},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{sessionState: ShowingReflection, shouldAskToContinue: tt.askToContinue}
+
+			m.handleReflectionDone(tt.msg)
+
+			if m.sessionState != tt.want {
+				t.Fatalf("session state = %v, want %v", m.sessionState, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleTodoPicked_CountsCompletedPomodoros(t *testing.T) {
+	todo := &db.Todo{ID: 3, Title: "write the parser", Estimate: 2}
+	m := Model{sessionState: ShowingPicker, currentTaskType: config.WorkTask}
+
+	m.handleTodoPicked(picker.PickedMsg{Todo: todo})
+
+	if m.sessionState != Running || m.sessionTodoID() != 3 {
+		t.Fatalf("session state = %v, todo = %d, want running on todo 3", m.sessionState, m.sessionTodoID())
+	}
+
+	m.elapsed = 25 * time.Minute
+	m.recordSession(db.SkippedOutcome)
+	m.recordSession(db.CompletedOutcome)
+
+	if todo.Pomodoros != 1 {
+		t.Fatalf("pomodoros = %d, want 1", todo.Pomodoros)
+	}
+
+	m.currentTaskType = config.BreakTask
+	if m.sessionTodoID() != 0 {
+		t.Fatalf("break session is spent on todo %d", m.sessionTodoID())
+	}
+}
+
+func TestRecordSession_LinksTodo(t *testing.T) {
+	database, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	// every connection to :memory: opens a new database
+	database.SetMaxOpenConns(1)
+
+	if _, err := db.Migrate(database, ""); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	todos := db.NewTodoRepo(database)
+	id, err := todos.AddTodo("write the parser", 2)
+	if err != nil {
+		t.Fatalf("add todo: %v", err)
+	}
+
+	m := Model{
+		sessionState:    Running,
+		currentTaskType: config.WorkTask,
+		currentTodo:     &db.Todo{ID: id},
+		repo:            db.NewSessionRepo(database),
+		elapsed:         25 * time.Minute,
+	}
+	m.recordSession(db.CompletedOutcome)
+
+	todo, err := todos.GetTodo(id)
+	if err != nil {
+		t.Fatalf("get todo: %v", err)
+	}
+
+	if todo.Pomodoros != 1 {
+		t.Fatalf("pomodoros = %d, want 1", todo.Pomodoros)
+	}
+}
+
+func TestHandleKeys_LogsInterruptionWithoutStopping(t *testing.T) {
+	m := Model{
+		sessionState:    Running,
+		currentTaskType: config.WorkTask,
+		interruptionLog: interruption.New(),
+		elapsed:         10 * time.Minute,
+	}
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("e")},
+		// typed into the note instead of skipping or quitting
+		{Type: tea.KeyRunes, Runes: []rune("quick question")},
+		{Type: tea.KeyEnter},
+	}
+
+	var cmd tea.Cmd
+	for _, msg := range keys {
+		cmd = m.handleKeys(msg)
+	}
+
+	logged, ok := cmd().(interruption.LoggedMsg)
+	if !ok {
+		t.Fatalf("expected a LoggedMsg")
+	}
+	m.handleInterruptionLogged(logged)
+
+	if m.sessionState != Running || m.interruptionLog.Active() {
+		t.Fatalf("session state = %v (input active: %v), want running", m.sessionState, m.interruptionLog.Active())
+	}
+
+	if len(m.interruptions) != 1 {
+		t.Fatalf("got %d interruptions, want 1", len(m.interruptions))
+	}
+	if got := m.interruptions[0]; got.Kind != db.ExternalInterruption || got.Note != "quick question" {
+		t.Fatalf("interruption = %+v, want an external one with its note", got)
+	}
+
+	// an interruption still being typed is kept when the session ends
+	m.handleKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m.recordSession(db.CompletedOutcome)
+
+	if m.interruptionLog.Active() || m.interruptions != nil {
+		t.Fatalf("interruptions were not cleared after recording the session")
+	}
+}
+
+func TestPause_RecordsIntervals(t *testing.T) {
+	m := Model{sessionState: Running, currentTaskType: config.WorkTask}
+
+	m.pause()
+	m.pauseStartedAt = m.pauseStartedAt.Add(-2 * time.Minute)
+	m.resume()
+
+	m.pause()
+	m.pauseStartedAt = m.pauseStartedAt.Add(-time.Minute)
+
+	if m.sessionState != Paused || len(m.pauses) != 1 {
+		t.Fatalf("session state = %v with %d pauses, want paused with 1 finished pause", m.sessionState, len(m.pauses))
+	}
+
+	if got := m.pausedDuration().Round(time.Minute); got != 3*time.Minute {
+		t.Fatalf("paused duration = %v, want 3m including the current pause", got)
+	}
+
+	// quitting while paused ends the current pause
+	m.endPause()
+	if len(m.pauses) != 2 || !m.pauseStartedAt.IsZero() {
+		t.Fatalf("got %d pauses (current started at %v), want 2 finished pauses", len(m.pauses), m.pauseStartedAt)
+	}
+}
+
+func TestHandleNotificationFailed(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			"one notifier",
+			errors.Join(&actions.NotifierError{Type: "ntfy", Err: errors.New("connection refused")}),
+			"notification failed: ntfy notifier: connection refused",
+		},
+		{
+			"several notifiers",
+			errors.Join(
+				&actions.NotifierError{Type: "desktop", Err: errors.New("no daemon")},
+				&actions.NotifierError{Index: 2, Type: "webhook", Err: errors.New("timeout")},
+			),
+			"2 notifiers failed: desktop, webhook",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{}
+			if cmd := m.handleNotificationFailed(notificationFailedMsg{err: tt.err}); cmd == nil {
+				t.Fatalf("expected a command clearing the status")
+			}
+
+			if m.configStatus != tt.want || !m.configStatusError {
+				t.Fatalf("status = %q (error: %v), want %q", m.configStatus, m.configStatusError, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlePauseTick_MaxPause(t *testing.T) {
+	old := config.C
+	t.Cleanup(func() { config.C = old })
+
+	testCases := []struct {
+		name         string
+		pause        config.Pause
+		pausedFor    time.Duration
+		want         SessionState
+		wantReminded bool
+	}{
+		{"no limit", config.Pause{OnMax: config.PauseEnd}, time.Hour, Paused, false},
+		{"below the limit", config.Pause{Max: 10 * time.Minute, OnMax: config.PauseEnd}, 5 * time.Minute, Paused, false},
+		{"reminds", config.Pause{Max: 10 * time.Minute, OnMax: config.PauseRemind}, 10 * time.Minute, Paused, true},
+		{"ends the session", config.Pause{Max: 10 * time.Minute, OnMax: config.PauseEnd}, 11 * time.Minute, Quitting, false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			config.C.Pause = tt.pause
+
+			m := Model{sessionState: Running, currentTaskType: config.WorkTask}
+			m.pause()
+			m.pauseStartedAt = m.pauseStartedAt.Add(-tt.pausedFor)
+
+			if cmd := m.handlePauseTick(pauseTickMsg{id: m.pauseID}); cmd == nil {
+				t.Fatalf("expected a command")
+			}
+
+			if m.sessionState != tt.want || m.pauseReminded != tt.wantReminded {
+				t.Fatalf("session state = %v (reminded: %v), want %v (reminded: %v)",
+					m.sessionState, m.pauseReminded, tt.want, tt.wantReminded)
+			}
+		})
+	}
+}
+
+func TestHandleIdleTime_PausesAndDiscardsIdleTime(t *testing.T) {
+	m := Model{
+		sessionState:    Running,
+		currentTaskType: config.WorkTask,
+		idleDetector:    &idle.Fake{},
+		idleAfter:       3 * time.Minute,
+		duration:        25 * time.Minute,
+		elapsed:         10 * time.Minute,
+	}
+
+	m.handleIdleTime(idleTimeMsg{idle: 2 * time.Minute})
+	if m.sessionState != Running {
+		t.Fatalf("session paused before being idle long enough")
+	}
+
+	m.handleIdleTime(idleTimeMsg{idle: 3 * time.Minute})
+	if m.sessionState != Paused || !m.idlePaused || m.awayCounted != 3*time.Minute {
+		t.Fatalf("session state = %v (idle paused: %v, counted %v), want paused after 3m", m.sessionState, m.idlePaused, m.awayCounted)
+	}
+
+	// still away
+	m.pauseStartedAt = m.pauseStartedAt.Add(-5 * time.Minute)
+	m.handleIdleTime(idleTimeMsg{idle: 8 * time.Minute})
+	if m.idleReturned {
+		t.Fatalf("returned without any input")
+	}
+
+	// back at the keyboard
+	m.handleIdleTime(idleTimeMsg{idle: time.Second})
+	if !m.idleReturned {
+		t.Fatalf("return was not detected")
+	}
+
+	m.handleKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+
+	if m.sessionState != Running || m.idlePaused || m.idleReturned {
+		t.Fatalf("session state = %v (idle paused: %v), want running again", m.sessionState, m.idlePaused)
+	}
+	if m.elapsed != 7*time.Minute {
+		t.Fatalf("elapsed = %v, want 7m without the idle time", m.elapsed)
+	}
+	if got := m.pausedDuration().Round(time.Minute); got != 8*time.Minute {
+		t.Fatalf("paused duration = %v, want 8m including the idle time", got)
+	}
+}
+
+func TestHandleIdleTime_IgnoresBreaks(t *testing.T) {
+	m := Model{sessionState: Running, currentTaskType: config.BreakTask, idleAfter: time.Minute}
+
+	m.handleIdleTime(idleTimeMsg{idle: time.Hour})
+
+	if m.sessionState != Running {
+		t.Fatalf("break session was paused")
+	}
+}
+
+func TestUpdateWindowTitle(t *testing.T) {
+	old := config.C
+	t.Cleanup(func() { config.C = old })
+	config.C.Terminal.WindowTitle = true
+
+	m := Model{
+		sessionState: Running,
+		currentTask:  config.Task{Title: "work session"},
+		timer:        timer.New(12*time.Minute + 34*time.Second),
+	}
+
+	if cmd := m.updateWindowTitle(); cmd == nil || m.windowTitle != "12:34 — work session — pomo" {
+		t.Fatalf("window title = %q", m.windowTitle)
+	}
+
+	// unchanged titles are not set again
+	if cmd := m.updateWindowTitle(); cmd != nil {
+		t.Fatalf("unchanged title was set again")
+	}
+
+	// a session ending while unfocused is urgent until focused
+	m.handleFocusChange(tea.BlurMsg{})
+	m.sessionState = ShowingConfirm
+	m.urgent = m.unfocused
+	m.updateWindowTitle()
+
+	if m.windowTitle != "🔔 work session — done! — pomo" {
+		t.Fatalf("window title = %q, want the urgent indicator", m.windowTitle)
+	}
+
+	m.handleFocusChange(tea.FocusMsg{})
+	m.updateWindowTitle()
+
+	if m.urgent || m.windowTitle != "work session — done! — pomo" {
+		t.Fatalf("window title = %q, want the urgent indicator cleared", m.windowTitle)
+	}
+
+	// the title is reset when quitting
+	m.sessionState = Quitting
+	if cmd := m.updateWindowTitle(); cmd == nil || m.windowTitle != "" {
+		t.Fatalf("window title = %q, want it reset", m.windowTitle)
+	}
+}
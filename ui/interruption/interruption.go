@@ -0,0 +1,103 @@
+// Package interruption provides the input to log an interruption while the timer keeps running.
+package interruption
+
+import (
+	"strings"
+
+	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/Bahaaio/pomo/ui/colors"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	noteWidth     = 30
+	noteCharLimit = 80
+)
+
+var labelStyle = lipgloss.NewStyle().Foreground(colors.DimGray)
+
+// LoggedMsg is sent when an interruption was logged.
+type LoggedMsg struct {
+	Kind db.InterruptionKind
+	Note string
+}
+
+type Model struct {
+	input  textinput.Model
+	kind   db.InterruptionKind
+	active bool
+	help   help.Model
+}
+
+func New() Model {
+	input := textinput.New()
+	input.Placeholder = i18n.T("what happened? (optional)")
+	input.CharLimit = noteCharLimit
+	input.Width = noteWidth
+
+	return Model{
+		input: input,
+		help:  help.New(),
+	}
+}
+
+// Start opens the input for an interruption of the given kind.
+func (m *Model) Start(kind db.InterruptionKind) tea.Cmd {
+	m.kind = kind
+	m.active = true
+	m.input.SetValue("")
+	return m.input.Focus()
+}
+
+// Active reports whether an interruption is being logged.
+func (m Model) Active() bool {
+	return m.active
+}
+
+// Close closes the input and returns the interruption typed so far.
+func (m *Model) Close() LoggedMsg {
+	m.active = false
+	m.input.Blur()
+
+	return LoggedMsg{Kind: m.kind, Note: strings.TrimSpace(m.input.Value())}
+}
+
+func (m Model) View() string {
+	label := labelStyle.Render(i18n.T(string(m.kind) + " interruption:"))
+
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		label+" "+m.input.View(),
+		m.help.View(i18n.KeyMap{KeyMap: Keys}),
+	)
+}
+
+func (m *Model) HandleKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, Keys.Log):
+		logged := m.Close()
+		return func() tea.Msg {
+			return logged
+		}
+
+	case key.Matches(msg, Keys.Cancel):
+		m.Close()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return cmd
+}
+
+// Update forwards other messages, e.g. cursor blinks, to the text input.
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return cmd
+}
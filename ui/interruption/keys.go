@@ -0,0 +1,32 @@
+package interruption
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type KeyMap struct {
+	Log    key.Binding
+	Cancel key.Binding
+}
+
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Log,
+		k.Cancel,
+	}
+}
+
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+var Keys = KeyMap{
+	Log: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "log"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
+}
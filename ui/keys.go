@@ -9,6 +9,8 @@ type KeyMap struct {
 	Reset    key.Binding
 	Pause    key.Binding
 	Skip     key.Binding
+	Internal key.Binding
+	External key.Binding
 	Quit     key.Binding
 }
 
@@ -18,6 +20,8 @@ func (k KeyMap) ShortHelp() []key.Binding {
 		k.Pause,
 		k.Reset,
 		k.Skip,
+		k.Internal,
+		k.External,
 		k.Quit,
 	}
 }
@@ -43,8 +47,44 @@ var keyMap = KeyMap{
 		key.WithKeys("s"),
 		key.WithHelp("s", "skip"),
 	),
+	Internal: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "internal interruption"),
+	),
+	External: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "external interruption"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("ctrl+c", "q"),
 		key.WithHelp("q", "quit"),
 	),
 }
+
+// IdleKeyMap is shown when returning to a session that was paused automatically
+type IdleKeyMap struct {
+	Discard key.Binding
+	Keep    key.Binding
+}
+
+func (k IdleKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Discard,
+		k.Keep,
+	}
+}
+
+func (k IdleKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+var idleKeyMap = IdleKeyMap{
+	Discard: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "discard idle time"),
+	),
+	Keep: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "keep and resume"),
+	),
+}
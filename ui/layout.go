@@ -2,9 +2,14 @@ package ui
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/Bahaaio/pomo/config"
+	"github.com/Bahaaio/pomo/i18n"
 	"github.com/Bahaaio/pomo/ui/ascii"
 	"github.com/Bahaaio/pomo/ui/colors"
+	"github.com/Bahaaio/pomo/ui/picker"
+	"github.com/charmbracelet/lipgloss"
 )
 
 const (
@@ -12,8 +17,18 @@ const (
 	margin             = 4
 	padding            = 2
 	separator          = " — "
-	pausedIndicator    = "(paused)"
+	pausedIndicator    = "paused"
+	awayIndicator      = "away"
+	urgentIndicator    = "🔔"
 	completedIndicator = "done!"
+	todoPomodorosText  = "pomodoros"
+)
+
+var (
+	todoStyle              = lipgloss.NewStyle().Foreground(colors.DimGray)
+	pausedTimeStyle        = lipgloss.NewStyle().Foreground(colors.DimGray)
+	configStatusStyle      = lipgloss.NewStyle().Foreground(colors.DimGray)
+	configStatusErrorStyle = lipgloss.NewStyle().Foreground(colors.ErrorMessageFg)
 )
 
 func (m *Model) buildMainContent() string {
@@ -31,36 +46,54 @@ func (m *Model) buildMainContent() string {
 	return content
 }
 
+// shows the todo of work sessions under the title
+func (m *Model) buildTodo() string {
+	if m.sessionTodoID() == 0 {
+		return ""
+	}
+
+	todo := fmt.Sprintf("%s (%s %s)", m.currentTodo.Title, picker.FormatPomodoros(*m.currentTodo), i18n.T(todoPomodorosText))
+	return "\n" + todoStyle.Render(todo)
+}
+
 func (m *Model) buildStatusIndicators() string {
 	if m.timer.Timedout() {
-		return separator + completedIndicator
+		return separator + i18n.T(completedIndicator)
 	}
 
 	if m.sessionState == Paused {
-		return " " + pausedIndicator
+		indicator := pausedIndicator
+		if m.idlePaused {
+			indicator = awayIndicator
+		}
+
+		return fmt.Sprintf(" (%s %s)", i18n.T(indicator), formatPaused(time.Since(m.pauseStartedAt)))
 	}
 
 	return ""
 }
 
+// shows the paused time of the session under the title
+func (m *Model) buildPausedTime() string {
+	paused := m.pausedDuration()
+	if paused < time.Second {
+		return ""
+	}
+
+	return "\n" + pausedTimeStyle.Render(i18n.Tf("%s paused in total", formatPaused(paused)))
+}
+
+func formatPaused(d time.Duration) string {
+	return d.Truncate(time.Second).String()
+}
+
 func (m *Model) buildProgressBar() string {
 	return "\n\n" + m.progressBar.View() + "\n"
 }
 
 // returns time left as a string in HH:MM:SS format
 func (m *Model) buildTimeLeft() string {
-	left := m.timer.Timeout
-	hours := int(left.Hours())
-	minutes := int(left.Minutes()) % 60
-	seconds := int(left.Seconds()) % 60
-
-	time := ""
-
-	// only show hours if they are non-zero
-	if hours > 0 {
-		time += fmt.Sprintf("%02d:", hours)
-	}
-	time += fmt.Sprintf("%02d:%02d", minutes, seconds)
+	time := formatTimeLeft(m.timer.Timeout)
 
 	if m.useTimerArt {
 		time = ascii.RenderNumber(time, m.timerFont)
@@ -77,6 +110,62 @@ func (m *Model) buildTimeLeft() string {
 	return time
 }
 
+func formatTimeLeft(left time.Duration) string {
+	hours := int(left.Hours())
+	minutes := int(left.Minutes()) % 60
+	seconds := int(left.Seconds()) % 60
+
+	formatted := ""
+
+	// only show hours if they are non-zero
+	if hours > 0 {
+		formatted += fmt.Sprintf("%02d:", hours)
+	}
+	formatted += fmt.Sprintf("%02d:%02d", minutes, seconds)
+
+	return formatted
+}
+
+// builds the terminal window title with the remaining time of the session
+func (m *Model) buildWindowTitle() string {
+	var title string
+
+	switch m.sessionState {
+	case Running:
+		title = formatTimeLeft(m.timer.Timeout) + separator + m.currentTask.Title
+	case Paused:
+		title = formatTimeLeft(m.timer.Timeout) + separator + m.currentTask.Title + " (" + i18n.T(pausedIndicator) + ")"
+	case ShowingPicker:
+		title = m.currentTask.Title
+	default:
+		title = m.currentTask.Title + separator + i18n.T(completedIndicator)
+	}
+
+	if m.urgent {
+		title = urgentIndicator + " " + title
+	}
+
+	return title + separator + config.AppName
+}
+
 func (m *Model) buildHelpView() string {
-	return m.help.View(keyMap)
+	return m.help.View(i18n.KeyMap{KeyMap: keyMap})
+}
+
+// asks whether the idle time counted before the automatic pause should be kept
+func (m *Model) buildIdlePrompt() string {
+	prompt := i18n.Tf("welcome back, %s of idle time was counted", formatPaused(m.awayCounted))
+	return lipgloss.JoinVertical(lipgloss.Center, prompt, m.help.View(i18n.KeyMap{KeyMap: idleKeyMap}))
+}
+
+func (m *Model) buildConfigStatus() string {
+	if m.configStatus == "" {
+		return ""
+	}
+
+	if m.configStatusError {
+		return configStatusErrorStyle.Render(m.configStatus)
+	}
+
+	return configStatusStyle.Render(m.configStatus)
 }
@@ -6,9 +6,13 @@ import (
 
 	"github.com/Bahaaio/pomo/config"
 	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/idle"
 	"github.com/Bahaaio/pomo/ui/ascii"
 	"github.com/Bahaaio/pomo/ui/colors"
 	"github.com/Bahaaio/pomo/ui/confirm"
+	"github.com/Bahaaio/pomo/ui/interruption"
+	"github.com/Bahaaio/pomo/ui/picker"
+	"github.com/Bahaaio/pomo/ui/reflection"
 	"github.com/Bahaaio/pomo/ui/summary"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/progress"
@@ -18,9 +22,12 @@ import (
 
 type Model struct {
 	// components
-	progressBar   progress.Model
-	confirmDialog confirm.Model
-	help          help.Model
+	progressBar      progress.Model
+	confirmDialog    confirm.Model
+	reflectionPrompt reflection.Model
+	todoPicker       picker.Model
+	interruptionLog  interruption.Model
+	help             help.Model
 
 	// timer
 	timer    timer.Model
@@ -30,12 +37,40 @@ type Model struct {
 	// state
 	width, height       int // window dimensions
 	shouldAskToContinue bool
+	shouldAskForNotes   bool
 	sessionState        SessionState
 	confirmStartTime    time.Time
 	currentTaskType     config.TaskType
 	currentTask         config.Task
 	sessionSummary      summary.SessionSummary
 	isShortSession      bool
+	profile             string
+	lastSessionID       int64             // id of the last recorded session, 0 if it wasn't saved
+	currentTodo         *db.Todo          // todo of the work sessions, nil if none was picked
+	interruptions       []db.Interruption // logged during the current session
+
+	// pauses of the current session
+	pauses         []db.Pause // finished pauses
+	pauseStartedAt time.Time  // zero if not paused
+	pauseID        int        // ignores ticks of previous pauses
+	pauseReminded  bool
+
+	// idle detection, the detector is nil if disabled
+	idleDetector idle.Detector
+	idleAfter    time.Duration
+	idlePaused   bool          // the session was paused automatically
+	awayCounted  time.Duration // idle time counted before the automatic pause
+	idleReturned bool          // there was input since the automatic pause
+
+	// terminal
+	windowTitle string // title last set, empty if never set
+	unfocused   bool   // the terminal reported losing focus
+	urgent      bool   // a session ended while unfocused
+
+	// config reload indicator
+	configStatus      string
+	configStatusError bool
+	configStatusID    int
 
 	// ASCII art
 	useTimerArt     bool
@@ -43,26 +78,18 @@ type Model struct {
 	asciiTimerStyle lipgloss.Style
 
 	// databse
-	repo *db.SessionRepo
+	repo  *db.SessionRepo
+	todos *db.TodoRepo
 }
 
 func NewModel(taskType config.TaskType, asciiArt config.ASCIIArt, askToContinue bool) Model {
 	task := taskType.GetTask()
 
-	var timerFont ascii.Font
-	timerStyle := lipgloss.NewStyle()
-
-	if asciiArt.Enabled {
-		timerFont = ascii.GetFont(asciiArt.Font)
-
-		timerColor := colors.GetColor(asciiArt.Color)
-		timerStyle = timerStyle.Foreground(timerColor)
-	}
-
 	sessionSummary := summary.SessionSummary{}
 
 	database, err := db.Connect()
 	var repo *db.SessionRepo
+	var todos *db.TodoRepo
 
 	if err != nil {
 		// gracefully handle database connection failure
@@ -73,27 +100,70 @@ func NewModel(taskType config.TaskType, asciiArt config.ASCIIArt, askToContinue
 		sessionSummary.SetDatabaseUnavailable()
 	} else {
 		repo = db.NewSessionRepo(database)
+		todos = db.NewTodoRepo(database)
 	}
 
-	return Model{
-		progressBar:   progress.New(progress.WithDefaultGradient()),
-		confirmDialog: confirm.New(),
-		help:          help.New(),
+	m := Model{
+		progressBar:      progress.New(progress.WithDefaultGradient()),
+		confirmDialog:    confirm.New(),
+		reflectionPrompt: reflection.New(),
+		todoPicker:       picker.New(),
+		interruptionLog:  interruption.New(),
+		help:             help.New(),
 
 		timer:    timer.New(task.Duration),
 		duration: task.Duration,
 
 		shouldAskToContinue: askToContinue,
+		shouldAskForNotes:   config.C.AskForNotes,
 		sessionState:        Running,
 		currentTaskType:     taskType,
-		currentTask:         *task,
+		currentTask:         task.Localized(),
 		sessionSummary:      sessionSummary,
+		profile:             config.C.Profile,
 
-		useTimerArt:     asciiArt.Enabled,
-		timerFont:       timerFont,
-		asciiTimerStyle: timerStyle,
+		repo:  repo,
+		todos: todos,
+	}
+
+	m.applyASCIIArt(asciiArt)
+	m.setupIdleDetection(config.C.Idle)
+
+	// ask for the todo of the first work session before starting the timer
+	if taskType == config.WorkTask && m.loadTodoPicker() {
+		m.sessionState = ShowingPicker
+	}
+
+	return m
+}
 
-		repo: repo,
+// starts detecting idle time if work sessions should be paused automatically
+func (m *Model) setupIdleDetection(idleConfig config.Idle) {
+	if idleConfig.After <= 0 {
+		return
+	}
+
+	detector, err := idle.New(idleConfig.Backend)
+	if err != nil {
+		log.Printf("idle detection unavailable: %v", err)
+		return
+	}
+
+	m.idleDetector = detector
+	m.idleAfter = idleConfig.After
+}
+
+// sets up the timer font and color
+func (m *Model) applyASCIIArt(asciiArt config.ASCIIArt) {
+	m.useTimerArt = asciiArt.Enabled
+	m.timerFont = ascii.Font{}
+	m.asciiTimerStyle = lipgloss.NewStyle()
+
+	if asciiArt.Enabled {
+		m.timerFont = ascii.GetFont(asciiArt.Font)
+
+		timerColor := colors.GetColor(asciiArt.Color)
+		m.asciiTimerStyle = m.asciiTimerStyle.Foreground(timerColor)
 	}
 }
 
@@ -103,6 +173,8 @@ const (
 	Running SessionState = iota
 	Paused
 	ShowingConfirm
+	ShowingReflection
+	ShowingPicker
 	Quitting
 )
 
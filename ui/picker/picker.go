@@ -0,0 +1,153 @@
+// Package picker provides the list to pick the todo of a work session from.
+package picker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/Bahaaio/pomo/ui/colors"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	noTodoText    = "no todo"
+	cursorText    = "> "
+	maxTitleRunes = 48
+)
+
+var (
+	promptStyle = lipgloss.NewStyle().
+			Align(lipgloss.Center).
+			Bold(true)
+
+	borderStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(colors.BorderFg).
+			Padding(1, 4)
+
+	selectedStyle = lipgloss.NewStyle().Foreground(colors.TimerFg).Bold(true)
+	countStyle    = lipgloss.NewStyle().Foreground(colors.DimGray)
+)
+
+// PickedMsg is sent when a todo was picked.
+// Todo is nil if the session isn't spent on a todo, Quit is set if the user quit.
+type PickedMsg struct {
+	Todo *db.Todo
+	Quit bool
+}
+
+type Model struct {
+	todos         []db.Todo
+	cursor        int // the entry after the todos is "no todo"
+	width, height int
+	help          help.Model
+}
+
+func New() Model {
+	return Model{help: help.New()}
+}
+
+// SetTodos replaces the todos to pick from and selects the todo with selectedID,
+// or the first one if there is none with that id.
+func (m *Model) SetTodos(todos []db.Todo, selectedID int64) {
+	m.todos = todos
+	m.cursor = 0
+
+	for i, todo := range todos {
+		if todo.ID == selectedID {
+			m.cursor = i
+		}
+	}
+}
+
+func (m Model) View(prompt string) string {
+	lines := make([]string, 0, len(m.todos)+1)
+
+	for i, todo := range m.todos {
+		line := truncate(todo.Title, maxTitleRunes) + " " + countStyle.Render(FormatPomodoros(todo))
+		lines = append(lines, m.renderEntry(i, line))
+	}
+
+	lines = append(lines, m.renderEntry(len(m.todos), i18n.T(noTodoText)))
+
+	list := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	dialog := lipgloss.JoinVertical(lipgloss.Center, promptStyle.Render(prompt), "", list)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, borderStyle.Render(dialog), "", m.help.View(i18n.KeyMap{KeyMap: Keys})),
+	)
+}
+
+func (m *Model) HandleKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, Keys.Up):
+		m.cursor = max(m.cursor-1, 0)
+		return nil
+
+	case key.Matches(msg, Keys.Down):
+		m.cursor = min(m.cursor+1, len(m.todos))
+		return nil
+
+	case key.Matches(msg, Keys.Select):
+		if m.cursor < len(m.todos) {
+			todo := m.todos[m.cursor]
+			return picked(PickedMsg{Todo: &todo})
+		}
+		return picked(PickedMsg{})
+
+	case key.Matches(msg, Keys.Skip):
+		return picked(PickedMsg{})
+
+	case key.Matches(msg, Keys.Quit):
+		return picked(PickedMsg{Quit: true})
+
+	default:
+		return nil
+	}
+}
+
+func (m *Model) HandleWindowResize(msg tea.WindowSizeMsg) tea.Cmd {
+	m.width = msg.Width
+	m.height = msg.Height
+	return nil
+}
+
+func (m Model) renderEntry(index int, text string) string {
+	if index == m.cursor {
+		return selectedStyle.Render(cursorText) + text
+	}
+
+	return strings.Repeat(" ", len(cursorText)) + text
+}
+
+func picked(msg PickedMsg) tea.Cmd {
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// FormatPomodoros formats the finished pomodoros of a todo against its estimate, e.g. 3/4
+func FormatPomodoros(todo db.Todo) string {
+	if todo.Estimate == 0 {
+		return strconv.Itoa(todo.Pomodoros)
+	}
+
+	return fmt.Sprintf("%d/%d", todo.Pomodoros, todo.Estimate)
+}
+
+func truncate(s string, maxRunes int) string {
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+
+	return string(runes[:maxRunes-1]) + "…"
+}
@@ -0,0 +1,82 @@
+package picker
+
+import (
+	"testing"
+
+	"github.com/Bahaaio/pomo/db"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHandleKeys(t *testing.T) {
+	todos := []db.Todo{
+		{ID: 1, Title: "write the parser"},
+		{ID: 2, Title: "review pull requests"},
+	}
+
+	testCases := []struct {
+		name       string
+		selectedID int64
+		keys       []tea.KeyMsg
+		want       int64 // id of the picked todo, 0 for no todo
+		wantQuit   bool
+	}{
+		{"first todo by default", 0, []tea.KeyMsg{{Type: tea.KeyEnter}}, 1, false},
+		{"keeps the selected todo", 2, []tea.KeyMsg{{Type: tea.KeyEnter}}, 2, false},
+		{"moves down", 0, []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("j")}, {Type: tea.KeyEnter}}, 2, false},
+		{"stays at the top", 0, []tea.KeyMsg{{Type: tea.KeyUp}, {Type: tea.KeyEnter}}, 1, false},
+		{
+			"no todo after the todos", 0,
+			[]tea.KeyMsg{{Type: tea.KeyDown}, {Type: tea.KeyDown}, {Type: tea.KeyDown}, {Type: tea.KeyEnter}},
+			0, false,
+		},
+		{"skip", 2, []tea.KeyMsg{{Type: tea.KeyEsc}}, 0, false},
+		{"quit", 0, []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("q")}}, 0, true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			m.SetTodos(todos, tt.selectedID)
+
+			var cmd tea.Cmd
+			for _, msg := range tt.keys {
+				cmd = m.HandleKeys(msg)
+			}
+
+			if cmd == nil {
+				t.Fatalf("no message sent")
+			}
+
+			got, ok := cmd().(PickedMsg)
+			if !ok {
+				t.Fatalf("message is not a PickedMsg")
+			}
+
+			var gotID int64
+			if got.Todo != nil {
+				gotID = got.Todo.ID
+			}
+
+			if gotID != tt.want || got.Quit != tt.wantQuit {
+				t.Fatalf("picked todo %d (quit: %v), want %d (quit: %v)", gotID, got.Quit, tt.want, tt.wantQuit)
+			}
+		})
+	}
+}
+
+func TestFormatPomodoros(t *testing.T) {
+	testCases := []struct {
+		todo db.Todo
+		want string
+	}{
+		{db.Todo{Pomodoros: 2}, "2"},
+		{db.Todo{Pomodoros: 2, Estimate: 4}, "2/4"},
+		{db.Todo{Pomodoros: 5, Estimate: 3}, "5/3"},
+	}
+
+	for _, tt := range testCases {
+		if got := FormatPomodoros(tt.todo); got != tt.want {
+			t.Fatalf("FormatPomodoros(%+v) = %q, want %q", tt.todo, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package reflection
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type KeyMap struct {
+	RateUp   key.Binding
+	RateDown key.Binding
+	Submit   key.Binding
+	Skip     key.Binding
+	Quit     key.Binding
+}
+
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.RateUp,
+		k.Submit,
+		k.Skip,
+		k.Quit,
+	}
+}
+
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+// letters are typed into the note, so only special keys are bound
+var Keys = KeyMap{
+	RateUp: key.NewBinding(
+		key.WithKeys("up"),
+		key.WithHelp("↑/↓", "rate"),
+	),
+	RateDown: key.NewBinding(
+		key.WithKeys("down"),
+	),
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "save"),
+	),
+	Skip: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "skip"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+}
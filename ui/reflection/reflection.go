@@ -0,0 +1,152 @@
+// Package reflection provides the prompt for a note and rating after a work session.
+package reflection
+
+import (
+	"strings"
+
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/Bahaaio/pomo/ui/colors"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	MaxRating = 5
+
+	noteWidth     = 40
+	noteCharLimit = 280
+	ratedStar     = "★"
+	unratedStar   = "☆"
+)
+
+var (
+	promptStyle = lipgloss.NewStyle().
+			Align(lipgloss.Center).
+			Bold(true)
+
+	borderStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(colors.BorderFg).
+			Padding(1, 4)
+
+	ratedStyle   = lipgloss.NewStyle().Foreground(colors.TimerFg)
+	unratedStyle = lipgloss.NewStyle().Foreground(colors.DimGray)
+)
+
+// DoneMsg is sent when the prompt is closed.
+// Note and Rating are empty if it was skipped, Quit is set if the user quit.
+type DoneMsg struct {
+	Note   string
+	Rating int
+	Quit   bool
+}
+
+type Model struct {
+	input         textinput.Model
+	rating        int
+	width, height int
+	help          help.Model
+}
+
+func New() Model {
+	input := textinput.New()
+	input.Placeholder = i18n.T("what got done?")
+	input.CharLimit = noteCharLimit
+	input.Width = noteWidth
+
+	return Model{
+		input: input,
+		help:  help.New(),
+	}
+}
+
+// Reset clears the previous note and rating and focuses the input.
+func (m *Model) Reset() tea.Cmd {
+	m.input.SetValue("")
+	m.rating = 0
+	return m.input.Focus()
+}
+
+func (m Model) View(prompt string) string {
+	prompt = promptStyle.Render(prompt)
+
+	dialog := lipgloss.JoinVertical(lipgloss.Center, prompt, "", m.input.View(), "", renderRating(m.rating))
+	ui := borderStyle.Render(dialog)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, ui, "", m.help.View(i18n.KeyMap{KeyMap: Keys})),
+	)
+}
+
+func (m *Model) HandleKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, Keys.Submit):
+		return m.done(false)
+
+	case key.Matches(msg, Keys.Skip):
+		m.input.SetValue("")
+		m.rating = 0
+		return m.done(false)
+
+	case key.Matches(msg, Keys.Quit):
+		return m.done(true)
+
+	case key.Matches(msg, Keys.RateUp):
+		m.rating = min(m.rating+1, MaxRating)
+		return nil
+
+	case key.Matches(msg, Keys.RateDown):
+		m.rating = max(m.rating-1, 0)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return cmd
+}
+
+// Update forwards other messages, e.g. cursor blinks, to the text input.
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return cmd
+}
+
+func (m *Model) HandleWindowResize(msg tea.WindowSizeMsg) tea.Cmd {
+	m.width = msg.Width
+	m.height = msg.Height
+	return nil
+}
+
+func (m *Model) done(quit bool) tea.Cmd {
+	m.input.Blur()
+
+	msg := DoneMsg{
+		Note:   strings.TrimSpace(m.input.Value()),
+		Rating: m.rating,
+		Quit:   quit,
+	}
+
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// FormatRating renders a rating as stars, e.g. ★★★☆☆, or an empty string if unrated.
+func FormatRating(rating int) string {
+	if rating <= 0 {
+		return ""
+	}
+
+	return strings.Repeat(ratedStar, rating) + strings.Repeat(unratedStar, MaxRating-rating)
+}
+
+func renderRating(rating int) string {
+	return ratedStyle.Render(strings.Repeat(ratedStar, rating)) +
+		unratedStyle.Render(strings.Repeat(unratedStar, MaxRating-rating))
+}
@@ -0,0 +1,85 @@
+package reflection
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHandleKeys(t *testing.T) {
+	testCases := []struct {
+		name string
+		keys []tea.KeyMsg
+		want DoneMsg
+	}{
+		{
+			name: "note and rating",
+			keys: []tea.KeyMsg{
+				{Type: tea.KeyRunes, Runes: []rune(" wrote tests ")},
+				{Type: tea.KeyUp}, {Type: tea.KeyUp}, {Type: tea.KeyUp}, {Type: tea.KeyDown},
+				{Type: tea.KeyEnter},
+			},
+			want: DoneMsg{Note: "wrote tests", Rating: 2},
+		},
+		{
+			name: "rating is capped",
+			keys: []tea.KeyMsg{
+				{Type: tea.KeyUp}, {Type: tea.KeyUp}, {Type: tea.KeyUp}, {Type: tea.KeyUp}, {Type: tea.KeyUp}, {Type: tea.KeyUp},
+				{Type: tea.KeyEnter},
+			},
+			want: DoneMsg{Rating: MaxRating},
+		},
+		{
+			name: "skip discards the note",
+			keys: []tea.KeyMsg{
+				{Type: tea.KeyRunes, Runes: []rune("draft")},
+				{Type: tea.KeyUp},
+				{Type: tea.KeyEsc},
+			},
+			want: DoneMsg{},
+		},
+		{
+			name: "quit keeps the note",
+			keys: []tea.KeyMsg{
+				{Type: tea.KeyRunes, Runes: []rune("almost done")},
+				{Type: tea.KeyCtrlC},
+			},
+			want: DoneMsg{Note: "almost done", Quit: true},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			m.Reset()
+
+			var cmd tea.Cmd
+			for _, msg := range tt.keys {
+				cmd = m.HandleKeys(msg)
+			}
+
+			if cmd == nil {
+				t.Fatalf("expected the last key to close the prompt")
+			}
+
+			got, ok := cmd().(DoneMsg)
+			if !ok {
+				t.Fatalf("expected a DoneMsg")
+			}
+
+			if got != tt.want {
+				t.Fatalf("DoneMsg = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRating(t *testing.T) {
+	if got := FormatRating(0); got != "" {
+		t.Fatalf("FormatRating(0) = %q, want empty string", got)
+	}
+
+	if got, want := FormatRating(3), "★★★☆☆"; got != want {
+		t.Fatalf("FormatRating(3) = %q, want %q", got, want)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"math"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
 	"github.com/Bahaaio/pomo/ui/colors"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -27,7 +29,6 @@ const (
 
 	minBarWidth = 3
 	spacing     = 2
-	daysInWeek  = 7
 )
 
 var spacer = strings.Repeat(paddingChar, spacing)
@@ -65,7 +66,7 @@ func (b *BarChart) calculateLayout(stats []db.DailyStat, maxDuration, scale time
 	yAxisLabelWidth := longestLabel
 	yAxisWidth := yAxisLabelWidth + 1 + 1 // length of label + space + tick char
 
-	barAreaWidth := spacing + (barWidth+spacing)*daysInWeek
+	barAreaWidth := spacing + (barWidth+spacing)*len(stats)
 
 	return chartLayout{
 		barHeight:       b.barHeight,
@@ -175,8 +176,12 @@ func (b *BarChart) buildLabels(stats []db.DailyStat) string {
 	var labels strings.Builder
 
 	for _, stat := range stats {
-		day := getDayLabel(stat.Date, b.barWidth)
-		labels.WriteString(day)
+		label := centerText(stat.Label, b.barWidth)
+		if stat.Label == "" {
+			label = getDayLabel(stat.Date, b.barWidth)
+		}
+
+		labels.WriteString(label)
 		labels.WriteString(spacer)
 	}
 
@@ -193,8 +198,7 @@ func getDayLabel(day string, width int) string {
 		return strings.Repeat(paddingChar, width)
 	}
 
-	// get first three letters of weekday
-	return centerText(t.Weekday().String()[:3], width)
+	return centerText(i18n.ShortWeekday(t.Weekday()), width)
 }
 
 func renderBar(totalRows, screenHeight, otherHeight, barWidth int, label string) string {
@@ -272,7 +276,7 @@ func getBarWidth(stats []db.DailyStat) int {
 	width := minBarWidth
 
 	for _, stat := range stats {
-		width = max(width, len(formatDurationLabel(stat.WorkDuration)))
+		width = max(width, len(formatDurationLabel(stat.WorkDuration)), utf8.RuneCountInString(stat.Label))
 	}
 
 	return width
@@ -310,12 +314,14 @@ func centerText(text string, width int) string {
 		return ""
 	}
 
-	if len(text) >= width {
-		return text[:width]
+	// labels may be translated, count characters instead of bytes
+	runes := []rune(text)
+	if len(runes) >= width {
+		return string(runes[:width])
 	}
 
-	left := (width - len(text)) / 2
-	right := width - len(text) - left
+	left := (width - len(runes)) / 2
+	right := width - len(runes) - left
 
 	return strings.Repeat(paddingChar, left) + text + strings.Repeat(paddingChar, right)
 }
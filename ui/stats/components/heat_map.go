@@ -1,17 +1,21 @@
 package components
 
 import (
+	"fmt"
+	"math"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/Bahaaio/pomo/config"
 	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
 	"github.com/Bahaaio/pomo/ui/colors"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	NumberOfMonths = 4
-
 	cellChar            = "󱓻 "
 	cellWidth           = 2
 	verticalSeparator   = "─"
@@ -19,9 +23,7 @@ const (
 
 	labelWidth        = 3
 	weekDayLabelWidth = labelWidth + 1 + 1 + cellWidth // including separator, space, and cellWidth*space "Sun │  "
-	maxMonthWeeks     = 5
-	monthGridWidth    = cellWidth * maxMonthWeeks
-	gridWidth         = monthGridWidth*NumberOfMonths + (NumberOfMonths)*cellWidth
+	paddingWidth      = 2
 )
 
 var (
@@ -34,23 +36,62 @@ var (
 
 	paddingStyle   = lipgloss.NewStyle().Padding(1)
 	leftAlignStyle = lipgloss.NewStyle().Align(lipgloss.Left)
+
+	// the work of a day up to each threshold is shaded lighter than the next
+	fixedThresholds = thresholds{30 * time.Minute, time.Hour, 2 * time.Hour}
 )
 
+// thresholds are the upper bounds of the work of the first three shades of active days
+type thresholds [3]time.Duration
+
 type monthGrid struct {
-	label    string
+	year     int
+	month    time.Month
 	numWeeks int
 	cells    [7][]string
+	weeks    []int // week number of each column
+}
+
+// width returns the number of columns of the grid
+func (g monthGrid) width() int {
+	return g.numWeeks * cellWidth
 }
 
-type HeatMap struct{}
+type HeatMap struct {
+	// Calendar decides the weekday of the first row and the week numbers
+	Calendar db.Calendar
+
+	// WeekNumbers adds a row with the week number of every other column
+	WeekNumbers bool
 
-func NewHeatMap() HeatMap {
-	return HeatMap{}
+	// Months is the number of months shown, at most config.MaxHeatMapMonths and 4 if unset
+	Months int
+
+	// Quantiles shades the days by the quartiles of the days with work
+	// instead of fixed durations
+	Quantiles bool
 }
 
-func (h *HeatMap) View(stats []db.DailyStat) string {
+// View renders the months up to end, today of the stats calendar or the last day of a past year.
+// The earliest months are left out if the heat map would be wider than maxWidth, 0 for no limit.
+func (h *HeatMap) View(stats []db.DailyStat, end time.Time, maxWidth int) string {
 	statsMap := buildStatsMap(stats)
-	grids := h.makeMonthGrids(statsMap)
+	grids := h.makeMonthGrids(statsMap, end, fixedThresholds)
+
+	// drop the oldest months that don't fit, keeping at least one
+	for len(grids) > 1 && maxWidth > 0 && paddingWidth+weekDayLabelWidth+gridsWidth(grids) > maxWidth {
+		grids = grids[1:]
+	}
+
+	// shade by the quartiles of the days that are shown
+	if h.Quantiles {
+		from := time.Date(grids[0].year, grids[0].month, 1, 0, 0, 0, 0, end.Location())
+		t := quantileThresholds(workDurations(stats, from, end))
+
+		for i, grid := range grids {
+			grids[i] = h.makeMonthGrid(grid.year, grid.month, end, statsMap, t)
+		}
+	}
 
 	// left align month labels
 	monthLabels := h.buildMonthLabels(grids)
@@ -58,6 +99,12 @@ func (h *HeatMap) View(stats []db.DailyStat) string {
 
 	dayLabels := h.buildWeekDayLabels()
 	grid := h.buildGrids(grids)
+
+	if h.WeekNumbers {
+		dayLabels += "\n" + padLabel(i18n.T("wk")) + " " + horizontalSeparator + strings.Repeat(" ", cellWidth)
+		grid += "\n" + h.buildWeekNumbers(grids)
+	}
+
 	legend := h.buildLegend()
 
 	center := lipgloss.JoinHorizontal(lipgloss.Left, dayLabels, grid)
@@ -67,15 +114,66 @@ func (h *HeatMap) View(stats []db.DailyStat) string {
 	return paddingStyle.Render(heatMap)
 }
 
+// returns the width of the grids including the space between them
+func gridsWidth(grids []monthGrid) int {
+	width := (len(grids) - 1) * cellWidth
+	for _, grid := range grids {
+		width += grid.width()
+	}
+
+	return width
+}
+
+// returns the work of the days with work from from to to
+func workDurations(stats []db.DailyStat, from, to time.Time) []time.Duration {
+	first, last := from.Format(db.DateFormat), to.Format(db.DateFormat)
+
+	var durations []time.Duration
+	for _, stat := range stats {
+		if stat.WorkDuration >= time.Second && stat.Date >= first && stat.Date <= last {
+			durations = append(durations, stat.WorkDuration)
+		}
+	}
+
+	return durations
+}
+
+// returns the quartiles of durations, so each shade covers a quarter of the days,
+// or the fixed thresholds if there are no durations
+func quantileThresholds(durations []time.Duration) thresholds {
+	if len(durations) == 0 {
+		return fixedThresholds
+	}
+
+	slices.Sort(durations)
+
+	// too few different durations for quartiles, use the darkest shades
+	// so the most work is always shaded darkest
+	if distinct := slices.Compact(slices.Clone(durations)); len(distinct) <= len(thresholds{}) {
+		var t thresholds
+		copy(t[len(t)-len(distinct)+1:], distinct)
+		return t
+	}
+
+	var t thresholds
+	for i := range t {
+		// nearest rank of the (i+1)/4 quantile
+		rank := int(math.Ceil(float64(len(durations)*(i+1))/4)) - 1
+		t[i] = durations[max(rank, 0)]
+	}
+
+	return t
+}
+
 func (h *HeatMap) buildLegend() string {
 	builder := strings.Builder{}
-	builder.WriteString("Less ")
+	builder.WriteString(i18n.T("Less") + " ")
 
 	for _, style := range styles {
 		builder.WriteString(style.Render(cellChar))
 	}
 
-	builder.WriteString(" More")
+	builder.WriteString(" " + i18n.T("More"))
 	return builder.String()
 }
 
@@ -111,30 +209,44 @@ func (h *HeatMap) buildGrids(grids []monthGrid) string {
 	return strings.Join(result, "\n")
 }
 
-func (h *HeatMap) makeMonthGrids(statsMap map[string]time.Duration) []monthGrid {
-	now := time.Now()
+func (h *HeatMap) makeMonthGrids(statsMap map[string]time.Duration, end time.Time, t thresholds) []monthGrid {
 	var grids []monthGrid
 
 	// build grid for each of the last N months
-	for i := NumberOfMonths - 1; i >= 0; i-- {
-		monthTime := now.AddDate(0, -i, 0)
-		grid := h.makeMonthGrid(monthTime.Year(), monthTime.Month(), now, statsMap)
+	for i := h.MonthCount() - 1; i >= 0; i-- {
+		monthTime := time.Date(end.Year(), end.Month()-time.Month(i), 1, 0, 0, 0, 0, end.Location())
+		grid := h.makeMonthGrid(monthTime.Year(), monthTime.Month(), end, statsMap, t)
 		grids = append(grids, grid)
 	}
 
 	return grids
 }
 
-func (h *HeatMap) makeMonthGrid(year int, month time.Month, today time.Time, statsMap map[string]time.Duration) monthGrid {
+// MonthCount returns the number of months the heat map shows if they fit.
+func (h *HeatMap) MonthCount() int {
+	if h.Months <= 0 {
+		return 4
+	}
+
+	return min(h.Months, config.MaxHeatMapMonths)
+}
+
+func (h *HeatMap) makeMonthGrid(year int, month time.Month, end time.Time, statsMap map[string]time.Duration, t thresholds) monthGrid {
 	// get first and last day of month
-	firstDay := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	firstDay := time.Date(year, month, 1, 0, 0, 0, 0, end.Location())
 	lastDay := firstDay.AddDate(0, 1, -1) // last day of month
 
 	// calculate number of weeks this month spans
-	startWeekday := int(firstDay.Weekday()) // 0=Sun, 6=Sat
+	startWeekday := h.row(firstDay.Weekday())
 	daysInMonth := lastDay.Day()
 	numWeeks := (startWeekday + daysInMonth + 6) / 7
 
+	// number the columns by the week of a day in them, which may be in another month
+	weeks := make([]int, numWeeks)
+	for i := range weeks {
+		_, weeks[i] = h.Calendar.Week(firstDay.AddDate(0, 0, i*7-startWeekday))
+	}
+
 	// initialize grid with empty cells
 	var cells [7][]string
 	for i := range cells {
@@ -146,25 +258,27 @@ func (h *HeatMap) makeMonthGrid(year int, month time.Month, today time.Time, sta
 
 	// fill in actual days
 	for day := 1; day <= daysInMonth; day++ {
-		date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+		date := time.Date(year, month, day, 0, 0, 0, 0, end.Location())
 
 		// skip future dates
-		if date.After(today) {
+		if date.After(end) {
 			continue
 		}
 
-		weekday := int(date.Weekday())
+		weekday := h.row(date.Weekday())
 		week := (startWeekday + day - 1) / 7
 
 		key := date.Format(db.DateFormat)
 		duration := statsMap[key]
-		cells[weekday][week] = renderCell(duration)
+		cells[weekday][week] = renderCell(duration, t)
 	}
 
 	return monthGrid{
-		label:    month.String()[:3],
+		year:     year,
+		month:    month,
 		numWeeks: numWeeks,
 		cells:    cells,
+		weeks:    weeks,
 	}
 }
 
@@ -177,12 +291,19 @@ func (h *HeatMap) buildMonthLabels(grids []monthGrid) string {
 	builder.WriteString(strings.Repeat(" ", weekDayLabelWidth-2)) // -2 for the icon and space
 
 	for i, grid := range grids {
+		// the first month and every january are labeled with their year
+		label := padLabel(i18n.ShortMonth(grid.month))
+		if i == 0 || grid.month == time.January {
+			label = i18n.FormatDate(time.Date(grid.year, grid.month, 1, 0, 0, 0, 0, time.UTC), "Jan 2006")
+		}
+
 		// center the label over the grid
-		leftPad := (monthGridWidth - labelWidth) / 2
-		rightPad := monthGridWidth - labelWidth - leftPad + 1
+		labelLength := utf8.RuneCountInString(label)
+		leftPad := max(grid.width()-labelLength, 0) / 2
+		rightPad := max(grid.width()-labelLength-leftPad, 0)
 
 		builder.WriteString(strings.Repeat(" ", leftPad))
-		builder.WriteString(grid.label)
+		builder.WriteString(label)
 		builder.WriteString(strings.Repeat(" ", rightPad))
 
 		// add separator spacing between months (except last)
@@ -192,7 +313,7 @@ func (h *HeatMap) buildMonthLabels(grids []monthGrid) string {
 	}
 
 	// add separator line
-	separator := strings.Repeat(verticalSeparator, weekDayLabelWidth+gridWidth)
+	separator := strings.Repeat(verticalSeparator, weekDayLabelWidth+gridsWidth(grids))
 
 	builder.WriteString("\n")
 	builder.WriteString(separator)
@@ -200,13 +321,38 @@ func (h *HeatMap) buildMonthLabels(grids []monthGrid) string {
 	return builder.String()
 }
 
+// labels every other column with its week number,
+// two-digit numbers of adjacent columns would run into each other
+func (h *HeatMap) buildWeekNumbers(grids []monthGrid) string {
+	var builder strings.Builder
+
+	for monthIdx, grid := range grids {
+		for i := 0; i < grid.numWeeks; i += 2 {
+			width := min(2, grid.numWeeks-i) * cellWidth
+			fmt.Fprintf(&builder, "%-*d", width, grid.weeks[i])
+		}
+
+		if monthIdx < len(grids)-1 {
+			builder.WriteString(strings.Repeat(" ", cellWidth))
+		}
+	}
+
+	return builder.String()
+}
+
+// returns the row of weekday, the first row is the start of the week
+func (h *HeatMap) row(weekday time.Weekday) int {
+	return (int(weekday) - int(h.Calendar.FirstWeekday()) + 7) % 7
+}
+
 func (h *HeatMap) buildWeekDayLabels() string {
-	days := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
 	builder := strings.Builder{}
 
-	for _, day := range days {
-		builder.WriteString(day + " " + horizontalSeparator + strings.Repeat(" ", cellWidth))
-		if day != "Sat" {
+	for row := range 7 {
+		day := time.Weekday((int(h.Calendar.FirstWeekday()) + row) % 7)
+
+		builder.WriteString(padLabel(i18n.ShortWeekday(day)) + " " + horizontalSeparator + strings.Repeat(" ", cellWidth))
+		if row < 6 {
 			builder.WriteString("\n")
 		}
 	}
@@ -214,19 +360,29 @@ func (h *HeatMap) buildWeekDayLabels() string {
 	return builder.String()
 }
 
-func renderCell(duration time.Duration) string {
-	style := getCellStyle(duration)
+// pads or cuts a weekday or month name to the label width
+func padLabel(label string) string {
+	runes := []rune(label)
+	if len(runes) > labelWidth {
+		return string(runes[:labelWidth])
+	}
+
+	return label + strings.Repeat(" ", labelWidth-len(runes))
+}
+
+func renderCell(duration time.Duration, t thresholds) string {
+	style := getCellStyle(duration, t)
 	return style.Render(cellChar)
 }
 
-func getCellStyle(duration time.Duration) lipgloss.Style {
+func getCellStyle(duration time.Duration, t thresholds) lipgloss.Style {
 	if duration < time.Second {
 		return style0
-	} else if duration <= time.Minute*30 {
+	} else if duration <= t[0] {
 		return style1
-	} else if duration <= time.Hour {
+	} else if duration <= t[1] {
 		return style2
-	} else if duration <= time.Hour*2 {
+	} else if duration <= t[2] {
 		return style3
 	} else {
 		return style4
@@ -0,0 +1,140 @@
+package components
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bahaaio/pomo/config"
+	"github.com/Bahaaio/pomo/db"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestHeatMapWeekDayLabels(t *testing.T) {
+	testCases := []struct {
+		firstWeekday time.Weekday
+		want         []string
+	}{
+		{time.Sunday, []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}},
+		{time.Monday, []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.firstWeekday.String(), func(t *testing.T) {
+			h := HeatMap{Calendar: db.Calendar{Location: time.UTC}.WithFirstWeekday(tt.firstWeekday)}
+
+			for i, line := range strings.Split(h.buildWeekDayLabels(), "\n") {
+				if !strings.HasPrefix(line, tt.want[i]) {
+					t.Fatalf("row %d = %q, want %s", i, line, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHeatMapMonthGrid(t *testing.T) {
+	// february 2026 starts on a sunday
+	today := time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		firstWeekday time.Weekday
+		wantWeeks    []int
+		wantNumbers  string
+	}{
+		{time.Sunday, []int{6, 7, 8, 9}, "6   8   "},
+		{time.Monday, []int{5, 6, 7, 8, 9}, "5   7   9 "},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.firstWeekday.String(), func(t *testing.T) {
+			h := HeatMap{Calendar: db.Calendar{Location: time.UTC}.WithFirstWeekday(tt.firstWeekday), WeekNumbers: true}
+			grid := h.makeMonthGrid(2026, time.February, today, map[string]time.Duration{}, fixedThresholds)
+
+			if len(grid.weeks) != len(tt.wantWeeks) {
+				t.Fatalf("weeks = %v, want %v", grid.weeks, tt.wantWeeks)
+			}
+			for i := range grid.weeks {
+				if grid.weeks[i] != tt.wantWeeks[i] {
+					t.Fatalf("weeks = %v, want %v", grid.weeks, tt.wantWeeks)
+				}
+			}
+
+			if got := h.buildWeekNumbers([]monthGrid{grid}); got != tt.wantNumbers {
+				t.Fatalf("week numbers = %q, want %q", got, tt.wantNumbers)
+			}
+		})
+	}
+}
+
+func TestQuantileThresholds(t *testing.T) {
+	testCases := []struct {
+		name      string
+		durations []time.Duration
+		want      thresholds
+	}{
+		{"no work", nil, fixedThresholds},
+		{"one day", []time.Duration{time.Hour}, thresholds{}},
+		{"two days", []time.Duration{2 * time.Hour, time.Hour}, thresholds{0, 0, time.Hour}},
+		{"three durations", []time.Duration{3 * time.Hour, time.Hour, 2 * time.Hour, time.Hour}, thresholds{0, time.Hour, 2 * time.Hour}},
+		{
+			"quartiles",
+			[]time.Duration{8 * time.Hour, time.Hour, 2 * time.Hour, 4 * time.Hour},
+			thresholds{time.Hour, 2 * time.Hour, 4 * time.Hour},
+		},
+		{
+			"uneven",
+			[]time.Duration{10 * time.Minute, 20 * time.Minute, 30 * time.Minute, 40 * time.Minute, 50 * time.Minute, 60 * time.Minute},
+			thresholds{20 * time.Minute, 30 * time.Minute, 50 * time.Minute},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quantileThresholds(tt.durations); got != tt.want {
+				t.Fatalf("quantileThresholds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkDurations(t *testing.T) {
+	stats := []db.DailyStat{
+		{Date: "2026-05-31", WorkDuration: 8 * time.Hour},
+		{Date: "2026-06-01", WorkDuration: time.Hour},
+		{Date: "2026-06-02"},
+		{Date: "2026-06-10", WorkDuration: 2 * time.Hour},
+		{Date: "2026-06-11", WorkDuration: 3 * time.Hour},
+	}
+
+	from := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.June, 10, 0, 0, 0, 0, time.UTC)
+
+	// days outside the shown months and days without work don't count
+	want := []time.Duration{time.Hour, 2 * time.Hour}
+	if got := workDurations(stats, from, to); !slices.Equal(got, want) {
+		t.Fatalf("workDurations() = %v, want %v", got, want)
+	}
+}
+
+func TestHeatMapView_DropsMonthsThatDontFit(t *testing.T) {
+	end := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	h := HeatMap{Calendar: db.Calendar{Location: time.UTC}, Months: config.MaxHeatMapMonths}
+
+	full := h.View(nil, end, 0)
+	for _, want := range []string{"Nov 2025", "Jan 2026", "Oct"} {
+		if !strings.Contains(full, want) {
+			t.Fatalf("expected %q in a year-long heat map, got %q", want, full)
+		}
+	}
+
+	narrow := h.View(nil, end, 80)
+	if width := lipgloss.Width(narrow); width > 80 {
+		t.Fatalf("heat map is %d columns wide, want at most 80", width)
+	}
+
+	// the earliest month shown is labeled with its year
+	if strings.Contains(narrow, "Nov 2025") || !strings.Contains(narrow, "2026") || !strings.Contains(narrow, "Oct") {
+		t.Fatalf("expected the last months of 2026 in a narrow heat map, got %q", narrow)
+	}
+}
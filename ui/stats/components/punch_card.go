@@ -0,0 +1,112 @@
+package components
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	hoursPerDay     = 24
+	hourLabelStep   = 3
+	punchCardIndent = labelWidth + 1 + 1 + 1 // "Mon │ "
+	noAverageStart  = "  -  "
+)
+
+// weekdays in the order of the punch card rows
+var punchCardWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// PunchCard shows the work duration by weekday and hour of the day,
+// with the average start time of each weekday next to its row.
+type PunchCard struct{}
+
+func NewPunchCard() PunchCard {
+	return PunchCard{}
+}
+
+func (p PunchCard) View(stats db.HourlyStats) string {
+	maxDuration := maxHourlyDuration(stats)
+
+	rows := []string{p.buildHourLabels()}
+	for _, weekday := range punchCardWeekdays {
+		rows = append(rows, p.buildRow(stats, weekday, maxDuration))
+	}
+
+	summary := i18n.T("no sessions yet")
+	if stats.Sessions > 0 {
+		summary = i18n.Tf("average start %s · %d sessions", FormatTimeOfDay(stats.AverageStart), stats.Sessions)
+	}
+
+	card := lipgloss.JoinVertical(lipgloss.Left, rows...)
+	return paddingStyle.Render(lipgloss.JoinVertical(lipgloss.Center, card, "", summary))
+}
+
+func (p PunchCard) buildHourLabels() string {
+	var builder strings.Builder
+	builder.WriteString(strings.Repeat(" ", punchCardIndent))
+
+	for hour := 0; hour < hoursPerDay; hour += hourLabelStep {
+		label := fmt.Sprintf("%-*d", hourLabelStep*cellWidth, hour)
+		builder.WriteString(label)
+	}
+
+	// header for the average start column
+	builder.WriteString(" " + i18n.T("start"))
+
+	return builder.String()
+}
+
+func (p PunchCard) buildRow(stats db.HourlyStats, weekday time.Weekday, maxDuration time.Duration) string {
+	var builder strings.Builder
+	builder.WriteString(padLabel(i18n.ShortWeekday(weekday)) + " " + horizontalSeparator + " ")
+
+	for hour := range hoursPerDay {
+		builder.WriteString(renderPunchCell(stats.Work[weekday][hour], maxDuration))
+	}
+
+	averageStart := noAverageStart
+	if stats.WeekdaySessions[weekday] > 0 {
+		averageStart = FormatTimeOfDay(stats.WeekdayAverageStarts[weekday])
+	}
+
+	builder.WriteString(" " + averageStart)
+
+	return builder.String()
+}
+
+// cells are shaded relative to the busiest hour, so the card shows when work happens
+// regardless of how much work the range has
+func renderPunchCell(duration, maxDuration time.Duration) string {
+	if duration <= 0 || maxDuration <= 0 {
+		return style0.Render(cellChar)
+	}
+
+	level := int(math.Ceil(float64(len(styles)-1) * float64(duration) / float64(maxDuration)))
+
+	return styles[level].Render(cellChar)
+}
+
+func maxHourlyDuration(stats db.HourlyStats) time.Duration {
+	var maxDuration time.Duration
+
+	for _, hours := range stats.Work {
+		for _, duration := range hours {
+			maxDuration = max(maxDuration, duration)
+		}
+	}
+
+	return maxDuration
+}
+
+// FormatTimeOfDay formats a duration since midnight as a clock time, e.g. 09:05.
+func FormatTimeOfDay(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%02d:%02d", int(d.Hours())%hoursPerDay, int(d.Minutes())%60)
+}
@@ -0,0 +1,57 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bahaaio/pomo/db"
+)
+
+func TestFormatTimeOfDay(t *testing.T) {
+	testCases := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{0, "00:00"},
+		{9*time.Hour + 5*time.Minute, "09:05"},
+		{23*time.Hour + 59*time.Minute + 40*time.Second, "00:00"},
+	}
+
+	for _, tt := range testCases {
+		if got := FormatTimeOfDay(tt.duration); got != tt.want {
+			t.Fatalf("FormatTimeOfDay(%v) = %q, want %q", tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestPunchCardView_ShowsAverageStarts(t *testing.T) {
+	var stats db.HourlyStats
+	stats.Work[time.Monday][9] = time.Hour
+	stats.Sessions = 2
+	stats.AverageStart = 9*time.Hour + 30*time.Minute
+	stats.WeekdaySessions[time.Monday] = 2
+	stats.WeekdayAverageStarts[time.Monday] = 9*time.Hour + 30*time.Minute
+
+	got := NewPunchCard().View(stats)
+
+	for _, want := range []string{"Mon │", "Sun │", "09:30", "average start 09:30 · 2 sessions"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in punch card, got %q", want, got)
+		}
+	}
+}
+
+func TestRenderPunchCell_ShadesRelativeToBusiestHour(t *testing.T) {
+	if got := renderPunchCell(0, time.Hour); got != style0.Render(cellChar) {
+		t.Fatalf("empty hour = %q, want the empty style", got)
+	}
+
+	if got := renderPunchCell(time.Hour, time.Hour); got != style4.Render(cellChar) {
+		t.Fatalf("busiest hour = %q, want the brightest style", got)
+	}
+
+	if got := renderPunchCell(time.Minute, time.Hour); got != style1.Render(cellChar) {
+		t.Fatalf("quiet hour = %q, want the dimmest non-empty style", got)
+	}
+}
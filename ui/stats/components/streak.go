@@ -1,9 +1,8 @@
 package components
 
 import (
-	"fmt"
-
 	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
 )
 
 type Streak struct{}
@@ -13,5 +12,16 @@ func NewStreak() Streak {
 }
 
 func (s Streak) View(streak db.StreakStats) string {
-	return fmt.Sprintf("󱐋 streak %vd · best %vd", streak.Current, streak.Best)
+	view := "󱐋 " + i18n.Tf(
+		"streak %vd · best %vd · weekly %vw · best %vw",
+		streak.Current, streak.Best,
+		streak.CurrentWeeks, streak.BestWeeks,
+	)
+
+	// freezes are only shown if the streak rules allow them
+	if streak.FreezesPerMonth > 0 {
+		view += i18n.Tf(" · %d/%d freezes left", streak.FreezesLeft, streak.FreezesPerMonth)
+	}
+
+	return view
 }
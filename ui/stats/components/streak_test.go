@@ -0,0 +1,34 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/Bahaaio/pomo/db"
+)
+
+func TestStreakView(t *testing.T) {
+	testCases := []struct {
+		name   string
+		streak db.StreakStats
+		want   string
+	}{
+		{
+			name:   "without freezes",
+			streak: db.StreakStats{Current: 3, Best: 12, CurrentWeeks: 2, BestWeeks: 5},
+			want:   "󱐋 streak 3d · best 12d · weekly 2w · best 5w",
+		},
+		{
+			name:   "with freezes",
+			streak: db.StreakStats{Current: 3, Best: 12, CurrentWeeks: 2, BestWeeks: 5, FreezesLeft: 1, FreezesPerMonth: 2},
+			want:   "󱐋 streak 3d · best 12d · weekly 2w · best 5w · 1/2 freezes left",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewStreak().View(tt.streak); got != tt.want {
+				t.Fatalf("View() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,11 +3,36 @@ package stats
 import "github.com/charmbracelet/bubbles/key"
 
 type KeyMap struct {
-	Quit key.Binding
+	Previous    key.Binding
+	Next        key.Binding
+	Current     key.Binding
+	Day         key.Binding
+	Week        key.Binding
+	Month       key.Binding
+	Year        key.Binding
+	Custom      key.Binding
+	Hours       key.Binding
+	WeekNumbers key.Binding
+	OlderYear   key.Binding
+	NewerYear   key.Binding
+	Confirm     key.Binding
+	Cancel      key.Binding
+	Quit        key.Binding
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{
+		k.Previous,
+		k.Next,
+		k.Current,
+		k.Day,
+		k.Week,
+		k.Month,
+		k.Year,
+		k.Custom,
+		k.Hours,
+		k.WeekNumbers,
+		k.OlderYear,
 		k.Quit,
 	}
 }
@@ -17,6 +42,62 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 }
 
 var Keys = KeyMap{
+	Previous: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←", "previous"),
+	),
+	Next: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→", "next"),
+	),
+	Current: key.NewBinding(
+		key.WithKeys("t", "home"),
+		key.WithHelp("t", "today"),
+	),
+	Day: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "day"),
+	),
+	Week: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "week"),
+	),
+	Month: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "month"),
+	),
+	Year: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "year"),
+	),
+	Custom: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "custom"),
+	),
+	Hours: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "hours"),
+	),
+	WeekNumbers: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "week numbers"),
+	),
+	OlderYear: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[/]", "heatmap year"),
+	),
+	NewerYear: key.NewBinding(
+		// shown with OlderYear
+		key.WithKeys("]"),
+	),
+	Confirm: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "apply"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("ctrl+c", "q"),
 		key.WithHelp("q", "quit"),
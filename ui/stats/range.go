@@ -0,0 +1,277 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
+)
+
+// RangeKind is the period a stats range covers.
+type RangeKind string
+
+const (
+	DayRange    RangeKind = "day"
+	WeekRange   RangeKind = "week"
+	MonthRange  RangeKind = "month"
+	YearRange   RangeKind = "year"
+	CustomRange RangeKind = "custom"
+
+	// separates the dates of a custom range, e.g. 2026-01-01..2026-01-31
+	rangeSeparator = ".."
+
+	// ranges up to this many days are charted by day, longer ones by week, month or year
+	maxDailyBars   = 7
+	maxWeeklyBars  = 9
+	maxMonthlyBars = 24
+)
+
+// Range is a period of days, From and To are inclusive dates of the stats calendar.
+type Range struct {
+	Kind     RangeKind
+	From, To time.Time
+
+	calendar db.Calendar
+}
+
+// NewRange returns the range of the given kind containing date.
+// A custom range defaults to the 30 days up to date.
+func NewRange(kind RangeKind, date time.Time, calendar db.Calendar) Range {
+	r := Range{Kind: kind, calendar: calendar}
+
+	switch kind {
+	case DayRange:
+		r.From, r.To = date, date
+	case WeekRange:
+		r.From = calendar.WeekStart(date)
+		r.To = r.From.AddDate(0, 0, 6)
+	case MonthRange:
+		r.From = date.AddDate(0, 0, 1-date.Day())
+		r.To = r.From.AddDate(0, 1, -1)
+	case YearRange:
+		r.From = time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, date.Location())
+		r.To = r.From.AddDate(1, 0, -1)
+	default:
+		r.Kind = CustomRange
+		r.From, r.To = date.AddDate(0, 0, -29), date
+	}
+
+	return r
+}
+
+// ParseRange parses a range such as "week", "30d", "2026-W09", "2026-02", "2026"
+// or "2026-01-01..2026-01-31", relative to today of the calendar.
+func ParseRange(value string, calendar db.Calendar) (Range, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	today := calendar.Today()
+
+	switch kind := RangeKind(value); kind {
+	case DayRange, WeekRange, MonthRange, YearRange:
+		return NewRange(kind, today, calendar), nil
+	case "today":
+		return NewRange(DayRange, today, calendar), nil
+	}
+
+	// last n days, e.g. 30d
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		if days < 1 {
+			return Range{}, fmt.Errorf("invalid range %q: must cover at least one day", value)
+		}
+
+		return Range{Kind: CustomRange, From: today.AddDate(0, 0, 1-days), To: today, calendar: calendar}, nil
+	}
+
+	if from, to, ok := strings.Cut(value, rangeSeparator); ok {
+		fromDate, err := parseDate(from, today.Location())
+		if err != nil {
+			return Range{}, err
+		}
+
+		toDate, err := parseDate(to, today.Location())
+		if err != nil {
+			return Range{}, err
+		}
+
+		if toDate.Before(fromDate) {
+			return Range{}, fmt.Errorf("invalid range %q: %s is before %s", value, to, from)
+		}
+
+		return Range{Kind: CustomRange, From: fromDate, To: toDate, calendar: calendar}, nil
+	}
+
+	// ISO 8601 week, e.g. 2026-w09
+	if year, week, ok := strings.Cut(value, "-w"); ok {
+		monday, err := parseWeek(year, week, today.Location())
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid range %q: %w", value, err)
+		}
+
+		return NewRange(WeekRange, monday, calendar), nil
+	}
+
+	if month, err := time.ParseInLocation("2006-01", value, today.Location()); err == nil {
+		return NewRange(MonthRange, month, calendar), nil
+	}
+
+	if year, err := time.ParseInLocation("2006", value, today.Location()); err == nil {
+		return NewRange(YearRange, year, calendar), nil
+	}
+
+	if date, err := parseDate(value, today.Location()); err == nil {
+		return NewRange(DayRange, date, calendar), nil
+	}
+
+	return Range{}, fmt.Errorf(
+		"invalid range %q: expected day, week, month, year, a number of days like 30d, "+
+			"a date, a week like 2026-W09, a month like 2026-01, a year or from..to",
+		value,
+	)
+}
+
+// returns the monday of an ISO 8601 week
+func parseWeek(yearValue, weekValue string, location *time.Location) (time.Time, error) {
+	year, yearErr := strconv.Atoi(yearValue)
+	week, weekErr := strconv.Atoi(weekValue)
+	if yearErr != nil || weekErr != nil {
+		return time.Time{}, errors.New("expected a week like 2026-W09")
+	}
+
+	// january 4th is always in the first week
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, location)
+	monday := jan4.AddDate(0, 0, (week-1)*7-(int(jan4.Weekday())+6)%7)
+
+	if y, w := monday.ISOWeek(); y != year || w != week {
+		return time.Time{}, fmt.Errorf("%d has no week %d", year, week)
+	}
+
+	return monday, nil
+}
+
+// Shift returns the range moved by n periods, backward if n is negative.
+// Custom ranges move by their length.
+func (r Range) Shift(n int) Range {
+	switch r.Kind {
+	case MonthRange:
+		return NewRange(r.Kind, r.From.AddDate(0, n, 0), r.calendar)
+	case YearRange:
+		return NewRange(r.Kind, r.From.AddDate(n, 0, 0), r.calendar)
+	default:
+		days := n * r.Days()
+		r.From, r.To = r.From.AddDate(0, 0, days), r.To.AddDate(0, 0, days)
+		return r
+	}
+}
+
+// Days returns the number of days in the range.
+func (r Range) Days() int {
+	// dates are midnights, rounding absorbs DST changes
+	return int(r.To.Sub(r.From).Round(24*time.Hour)/(24*time.Hour)) + 1
+}
+
+// Contains reports whether date falls in the range.
+func (r Range) Contains(date time.Time) bool {
+	return !date.Before(r.From) && !date.After(r.To)
+}
+
+// Title describes the range, e.g. "Feb 16 – Feb 22, 2026".
+func (r Range) Title() string {
+	switch r.Kind {
+	case DayRange:
+		return i18n.FormatDate(r.From, "Mon, Jan 2 2006")
+	case MonthRange:
+		return i18n.FormatDate(r.From, "January 2006")
+	case YearRange:
+		return r.From.Format("2006")
+	}
+
+	if r.From.Year() == r.To.Year() {
+		return i18n.FormatDate(r.From, "Jan 2") + " – " + i18n.FormatDate(r.To, "Jan 2, 2006")
+	}
+
+	return i18n.FormatDate(r.From, "Jan 2, 2006") + " – " + i18n.FormatDate(r.To, "Jan 2, 2006")
+}
+
+// String returns the range in the format accepted by [ParseRange].
+func (r Range) String() string {
+	return r.From.Format(db.DateFormat) + rangeSeparator + r.To.Format(db.DateFormat)
+}
+
+// groupStats groups the daily stats of the range into the bars of the chart:
+// by day for short ranges, by week for about a month and by month or year for longer ranges.
+// Weeks are labeled with their first day, or with their number if weekNumbers is set.
+func (r Range) groupStats(days []db.DailyStat, weekNumbers bool) []db.DailyStat {
+	switch {
+	case len(days) <= maxDailyBars:
+		return days
+	case len(days) <= maxWeeklyBars*7:
+		label := dateLabel("Jan 2")
+		if weekNumbers {
+			label = r.weekLabel
+		}
+
+		return groupDays(days, label, func(date time.Time) string {
+			return r.calendar.WeekStart(date).Format(db.DateFormat)
+		})
+	case len(days) <= maxMonthlyBars*31:
+		return groupDays(days, dateLabel("Jan"), func(date time.Time) string {
+			return date.Format("2006-01")
+		})
+	default:
+		return groupDays(days, dateLabel("2006"), func(date time.Time) string {
+			return date.Format("2006")
+		})
+	}
+}
+
+// weekLabel returns the number of the calendar week containing date, e.g. W09.
+func (r Range) weekLabel(date time.Time) string {
+	_, week := r.calendar.Week(date)
+	return i18n.Tf("W%02d", week)
+}
+
+// returns a label of the date in the layout of the current locale
+func dateLabel(layout string) func(date time.Time) string {
+	return func(date time.Time) string {
+		return i18n.FormatDate(date, layout)
+	}
+}
+
+// merges consecutive days with the same group key into one stat,
+// labeled with the first day of the group
+func groupDays(days []db.DailyStat, label func(date time.Time) string, groupKey func(date time.Time) string) []db.DailyStat {
+	var grouped []db.DailyStat
+	lastKey := ""
+
+	for _, day := range days {
+		date, err := time.Parse(db.DateFormat, day.Date)
+		if err != nil {
+			continue
+		}
+
+		if key := groupKey(date); len(grouped) == 0 || key != lastKey {
+			grouped = append(grouped, db.DailyStat{Date: day.Date, Label: label(date)})
+			lastKey = key
+		}
+
+		stat := &grouped[len(grouped)-1]
+		stat.WorkDuration += day.WorkDuration
+		stat.ScreenWorkDuration += day.ScreenWorkDuration
+		stat.OtherWorkDuration += day.OtherWorkDuration
+		stat.BreakDuration += day.BreakDuration
+	}
+
+	return grouped
+}
+
+func parseDate(value string, location *time.Location) (time.Time, error) {
+	date, err := time.ParseInLocation(db.DateFormat, strings.TrimSpace(value), location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+
+	return date, nil
+}
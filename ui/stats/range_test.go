@@ -0,0 +1,168 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bahaaio/pomo/db"
+)
+
+var utcCalendar = db.Calendar{Location: time.UTC}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseRange(t *testing.T) {
+	testCases := []struct {
+		value    string
+		wantKind RangeKind
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{value: "2026-02-10..2026-02-20", wantKind: CustomRange, wantFrom: date(2026, 2, 10), wantTo: date(2026, 2, 20)},
+		{value: "2026-02", wantKind: MonthRange, wantFrom: date(2026, 2, 1), wantTo: date(2026, 2, 28)},
+		{value: "2024", wantKind: YearRange, wantFrom: date(2024, 1, 1), wantTo: date(2024, 12, 31)},
+		{value: " 2026-02-11 ", wantKind: DayRange, wantFrom: date(2026, 2, 11), wantTo: date(2026, 2, 11)},
+		{value: "2026-W09", wantKind: WeekRange, wantFrom: date(2026, 2, 23), wantTo: date(2026, 3, 1)},
+		{value: "2026-w01", wantKind: WeekRange, wantFrom: date(2025, 12, 29), wantTo: date(2026, 1, 4)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRange(tt.value, utcCalendar)
+			if err != nil {
+				t.Fatalf("ParseRange() error = %v", err)
+			}
+
+			if got.Kind != tt.wantKind || !got.From.Equal(tt.wantFrom) || !got.To.Equal(tt.wantTo) {
+				t.Fatalf("ParseRange() = %s %s, want %s %s..%s",
+					got.Kind, got, tt.wantKind, tt.wantFrom.Format(db.DateFormat), tt.wantTo.Format(db.DateFormat))
+			}
+		})
+	}
+}
+
+func TestParseRange_RelativeToToday(t *testing.T) {
+	today := utcCalendar.Today()
+
+	testCases := []struct {
+		value    string
+		wantKind RangeKind
+		wantDays int
+	}{
+		{value: "today", wantKind: DayRange, wantDays: 1},
+		{value: "week", wantKind: WeekRange, wantDays: 7},
+		{value: "30d", wantKind: CustomRange, wantDays: 30},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRange(tt.value, utcCalendar)
+			if err != nil {
+				t.Fatalf("ParseRange() error = %v", err)
+			}
+
+			if got.Kind != tt.wantKind || got.Days() != tt.wantDays || !got.Contains(today) {
+				t.Fatalf("ParseRange() = %s %s (%d days), want %s of %d days containing today",
+					got.Kind, got, got.Days(), tt.wantKind, tt.wantDays)
+			}
+		})
+	}
+}
+
+func TestParseRange_Invalid(t *testing.T) {
+	for _, value := range []string{"", "fortnight", "0d", "2026-02-20..2026-02-10", "2026-13-01", "2025-W53", "2026-W00", "2026-Wx"} {
+		if _, err := ParseRange(value, utcCalendar); err == nil {
+			t.Fatalf("ParseRange(%q) expected an error", value)
+		}
+	}
+}
+
+func TestRangeShift(t *testing.T) {
+	testCases := []struct {
+		name     string
+		r        Range
+		n        int
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{
+			name:     "week starts on monday",
+			r:        NewRange(WeekRange, date(2026, 2, 11), utcCalendar),
+			n:        -1,
+			wantFrom: date(2026, 2, 2),
+			wantTo:   date(2026, 2, 8),
+		},
+		{
+			name:     "month keeps its length",
+			r:        NewRange(MonthRange, date(2026, 1, 31), utcCalendar),
+			n:        1,
+			wantFrom: date(2026, 2, 1),
+			wantTo:   date(2026, 2, 28),
+		},
+		{
+			name:     "custom moves by its length",
+			r:        Range{Kind: CustomRange, From: date(2026, 2, 1), To: date(2026, 2, 10), calendar: utcCalendar},
+			n:        1,
+			wantFrom: date(2026, 2, 11),
+			wantTo:   date(2026, 2, 20),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.r.Shift(tt.n)
+			if !got.From.Equal(tt.wantFrom) || !got.To.Equal(tt.wantTo) {
+				t.Fatalf("Shift() = %s, want %s..%s",
+					got, tt.wantFrom.Format(db.DateFormat), tt.wantTo.Format(db.DateFormat))
+			}
+		})
+	}
+}
+
+func TestGroupStats(t *testing.T) {
+	testCases := []struct {
+		name        string
+		r           Range
+		weekNumbers bool
+		wantLabels  []string
+	}{
+		{name: "days", r: NewRange(WeekRange, date(2026, 2, 11), utcCalendar), wantLabels: []string{"", "", "", "", "", "", ""}},
+		{name: "weeks", r: NewRange(MonthRange, date(2026, 2, 1), utcCalendar), wantLabels: []string{"Feb 1", "Feb 2", "Feb 9", "Feb 16", "Feb 23"}},
+		{name: "week numbers", r: NewRange(MonthRange, date(2026, 2, 1), utcCalendar), weekNumbers: true, wantLabels: []string{"W05", "W06", "W07", "W08", "W09"}},
+		{
+			name:        "sunday weeks",
+			r:           NewRange(MonthRange, date(2026, 2, 1), utcCalendar.WithFirstWeekday(time.Sunday)),
+			weekNumbers: true,
+			wantLabels:  []string{"W06", "W07", "W08", "W09"},
+		},
+		{name: "months", r: Range{Kind: CustomRange, From: date(2025, 12, 1), To: date(2026, 2, 20), calendar: utcCalendar}, wantLabels: []string{"Dec", "Jan", "Feb"}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var days []db.DailyStat
+			for d := tt.r.From; !d.After(tt.r.To); d = d.AddDate(0, 0, 1) {
+				days = append(days, db.DailyStat{Date: d.Format(db.DateFormat), WorkDuration: time.Hour})
+			}
+
+			got := tt.r.groupStats(days, tt.weekNumbers)
+			if len(got) != len(tt.wantLabels) {
+				t.Fatalf("groupStats() returned %d bars, want %d", len(got), len(tt.wantLabels))
+			}
+
+			var total time.Duration
+			for i, stat := range got {
+				if stat.Label != tt.wantLabels[i] {
+					t.Fatalf("bar %d label = %q, want %q", i, stat.Label, tt.wantLabels[i])
+				}
+				total += stat.WorkDuration
+			}
+
+			if want := time.Duration(len(days)) * time.Hour; total != want {
+				t.Fatalf("grouped work = %s, want %s", total, want)
+			}
+		})
+	}
+}
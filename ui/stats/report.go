@@ -0,0 +1,327 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
+	"github.com/Bahaaio/pomo/ui/stats/components"
+)
+
+// Report holds the statistics shown by pomo stats,
+// it is printed instead of the TUI by the --plain and --json flags.
+type Report struct {
+	Range         Range
+	AllTime       db.AllTimeStats
+	Totals        db.AllTimeStats
+	Days          []db.DailyStat
+	Outcomes      db.OutcomeStats
+	Interruptions db.InterruptionStats
+	Hours         db.HourlyStats
+	Today         db.DailyStat
+	Streak        db.StreakStats
+	Profiles      []db.ProfileStats
+}
+
+// FetchReport retrieves the statistics of statsRange from repo.
+func FetchReport(repo *db.SessionRepo, statsRange Range) (Report, error) {
+	report := Report{Range: statsRange}
+
+	allTime, err := repo.GetAllTimeStats()
+	if err != nil {
+		return report, errors.New("failed to fetch all-time stats")
+	}
+	report.AllTime = allTime
+
+	rangeStats, err := repo.GetRangeStats(statsRange.From, statsRange.To)
+	if err != nil {
+		return report, errors.New("failed to fetch range stats")
+	}
+	report.Totals = rangeStats.Totals
+	report.Days = rangeStats.Days
+	report.Outcomes = rangeStats.Outcomes
+	report.Interruptions = rangeStats.Interruptions
+
+	hours, err := repo.GetHourlyStats(statsRange.From, statsRange.To)
+	if err != nil {
+		return report, errors.New("failed to fetch hourly stats")
+	}
+	report.Hours = hours
+
+	today := repo.Calendar().Today()
+	todayStats, err := repo.GetDailyStats(today, today)
+	if err != nil {
+		return report, errors.New("failed to fetch today's stats")
+	}
+	report.Today = findDay(todayStats, today)
+
+	streak, err := repo.GetStreakStats()
+	if err != nil {
+		return report, errors.New("failed to fetch streak stats")
+	}
+	report.Streak = streak
+
+	profiles, err := repo.GetProfileStats()
+	if err != nil {
+		return report, errors.New("failed to fetch profile stats")
+	}
+	report.Profiles = profiles
+
+	return report, nil
+}
+
+// WritePlain writes the report as plain text without colors or icons.
+func (r Report) WritePlain(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "%s\t%s (%s)\n", i18n.T("range:"), r.Range.Title(), i18n.T(string(r.Range.Kind)))
+	fmt.Fprintf(tw, "%s\t%s\n", i18n.T("total:"), buildTotalsLine(r.Totals))
+	if outcomes := buildOutcomesLine(r.Outcomes); outcomes != "" {
+		fmt.Fprintf(tw, "%s\t%s\n", i18n.T("outcomes:"), outcomes)
+	}
+	if interruptions := buildInterruptionsLine(r.Interruptions); interruptions != "" {
+		fmt.Fprintf(tw, "%s\t%s\n", i18n.T("interruptions:"), interruptions)
+	}
+	fmt.Fprintf(tw, "%s\t%s\n", i18n.T("all time:"), buildTotalsLine(r.AllTime))
+	fmt.Fprintf(tw, "%s\t%s\n", i18n.T("today:"), buildDayLine(r.Today))
+	fmt.Fprintf(tw, "%s\t%s\n", i18n.T("streak:"), buildStreakLine(r.Streak))
+
+	if r.Hours.Sessions > 0 {
+		fmt.Fprintf(tw, "%s\t%s\n", i18n.T("average start:"), components.FormatTimeOfDay(r.Hours.AverageStart))
+	}
+
+	if profiles := buildProfilesLine(r.Profiles); profiles != "" {
+		fmt.Fprintf(tw, "%s\t%s\n", i18n.T("profiles:"), profiles)
+	}
+
+	fmt.Fprintln(tw, "\n"+i18n.T("DATE\tWORK\tSCREEN\tOTHER\tBREAK"))
+	for _, day := range r.Days {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			day.Date,
+			formatDurationCompact(day.WorkDuration),
+			formatDurationCompact(day.ScreenWorkDuration),
+			formatDurationCompact(day.OtherWorkDuration),
+			formatDurationCompact(day.BreakDuration),
+		)
+	}
+
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as indented JSON, durations are in seconds.
+func (r Report) WriteJSON(w io.Writer) error {
+	days := make([]jsonDay, 0, len(r.Days))
+	for _, day := range r.Days {
+		days = append(days, newJSONDay(day))
+	}
+
+	profiles := make([]jsonProfile, 0, len(r.Profiles))
+	for _, profile := range r.Profiles {
+		profiles = append(profiles, jsonProfile{Profile: profile.Profile, jsonTotals: newJSONTotals(profile.AllTimeStats)})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(jsonReport{
+		Range: jsonRange{
+			Kind: r.Range.Kind,
+			From: r.Range.From.Format(db.DateFormat),
+			To:   r.Range.To.Format(db.DateFormat),
+		},
+		Totals: newJSONTotals(r.Totals),
+		Outcomes: jsonOutcomes{
+			Sessions:              r.Outcomes.Sessions,
+			Completed:             r.Outcomes.Completed,
+			Extended:              r.Outcomes.Extended,
+			Skipped:               r.Outcomes.Skipped,
+			Quit:                  r.Outcomes.Quit,
+			CompletionRate:        r.Outcomes.CompletionRate(),
+			AverageOverrunSeconds: r.Outcomes.AverageOverrun().Seconds(),
+			AbandonedPerDay:       r.Outcomes.AbandonedPerDay(),
+		},
+		Interruptions: jsonInterruptions{
+			Internal: r.Interruptions.Internal,
+			External: r.Interruptions.External,
+		},
+		AllTime: newJSONTotals(r.AllTime),
+		Today:   newJSONDay(r.Today),
+		Days:    days,
+		Hours:   newJSONHours(r.Hours),
+		Streak: jsonStreak{
+			Current:         r.Streak.Current,
+			Best:            r.Streak.Best,
+			CurrentWeeks:    r.Streak.CurrentWeeks,
+			BestWeeks:       r.Streak.BestWeeks,
+			FreezesLeft:     r.Streak.FreezesLeft,
+			FreezesPerMonth: r.Streak.FreezesPerMonth,
+		},
+		Profiles: profiles,
+	})
+}
+
+func buildDayLine(stat db.DailyStat) string {
+	return i18n.Tf(
+		"work %s · screen %s · other %s · break %s",
+		formatDurationCompact(stat.WorkDuration),
+		formatDurationCompact(stat.ScreenWorkDuration),
+		formatDurationCompact(stat.OtherWorkDuration),
+		formatDurationCompact(stat.BreakDuration),
+	)
+}
+
+// like the streak component, without the icon
+func buildStreakLine(stats db.StreakStats) string {
+	line := i18n.Tf(
+		"%dd · best %dd · weekly %dw · best %dw",
+		stats.Current, stats.Best, stats.CurrentWeeks, stats.BestWeeks,
+	)
+
+	if stats.FreezesPerMonth > 0 {
+		line += i18n.Tf(" · %d/%d freezes left", stats.FreezesLeft, stats.FreezesPerMonth)
+	}
+
+	return line
+}
+
+// returns the stat of date, or an empty stat for it if there is none
+func findDay(stats []db.DailyStat, date time.Time) db.DailyStat {
+	day := date.Format(db.DateFormat)
+
+	for _, stat := range stats {
+		if stat.Date == day {
+			return stat
+		}
+	}
+
+	return db.DailyStat{Date: day}
+}
+
+// the JSON output is decoupled from the db models so it stays stable for scripts
+type jsonReport struct {
+	Range         jsonRange         `json:"range"`
+	Totals        jsonTotals        `json:"totals"`
+	Outcomes      jsonOutcomes      `json:"outcomes"`
+	Interruptions jsonInterruptions `json:"interruptions"`
+	AllTime       jsonTotals        `json:"allTime"`
+	Today         jsonDay           `json:"today"`
+	Days          []jsonDay         `json:"days"`
+	Hours         jsonHours         `json:"hours"`
+	Streak        jsonStreak        `json:"streak"`
+	Profiles      []jsonProfile     `json:"profiles"`
+}
+
+type jsonRange struct {
+	Kind RangeKind `json:"kind"`
+	From string    `json:"from"`
+	To   string    `json:"to"`
+}
+
+type jsonTotals struct {
+	Sessions     int     `json:"sessions"`
+	WorkSeconds  float64 `json:"workSeconds"`
+	BreakSeconds float64 `json:"breakSeconds"`
+}
+
+type jsonOutcomes struct {
+	Sessions              int     `json:"sessions"`
+	Completed             int     `json:"completed"`
+	Extended              int     `json:"extended"`
+	Skipped               int     `json:"skipped"`
+	Quit                  int     `json:"quit"`
+	CompletionRate        float64 `json:"completionRate"`
+	AverageOverrunSeconds float64 `json:"averageOverrunSeconds"`
+	AbandonedPerDay       float64 `json:"abandonedPerDay"`
+}
+
+type jsonInterruptions struct {
+	Internal int `json:"internal"`
+	External int `json:"external"`
+}
+
+type jsonDay struct {
+	Date          string  `json:"date"`
+	WorkSeconds   float64 `json:"workSeconds"`
+	ScreenSeconds float64 `json:"screenSeconds"`
+	OtherSeconds  float64 `json:"otherSeconds"`
+	BreakSeconds  float64 `json:"breakSeconds"`
+}
+
+type jsonHours struct {
+	// empty if there are no sessions
+	AverageStart string           `json:"averageStart"`
+	Sessions     int              `json:"sessions"`
+	Weekdays     []jsonHourlyWeek `json:"weekdays"`
+}
+
+type jsonHourlyWeek struct {
+	Weekday      string    `json:"weekday"`
+	AverageStart string    `json:"averageStart"`
+	Sessions     int       `json:"sessions"`
+	WorkSeconds  []float64 `json:"workSeconds"` // by hour of the day
+}
+
+type jsonStreak struct {
+	Current         int `json:"current"`
+	Best            int `json:"best"`
+	CurrentWeeks    int `json:"currentWeeks"`
+	BestWeeks       int `json:"bestWeeks"`
+	FreezesLeft     int `json:"freezesLeft"`
+	FreezesPerMonth int `json:"freezesPerMonth"`
+}
+
+type jsonProfile struct {
+	Profile string `json:"profile"`
+	jsonTotals
+}
+
+func newJSONTotals(stats db.AllTimeStats) jsonTotals {
+	return jsonTotals{
+		Sessions:     stats.TotalSessions,
+		WorkSeconds:  stats.TotalWorkDuration.Seconds(),
+		BreakSeconds: stats.TotalBreakDuration.Seconds(),
+	}
+}
+
+func newJSONDay(stat db.DailyStat) jsonDay {
+	return jsonDay{
+		Date:          stat.Date,
+		WorkSeconds:   stat.WorkDuration.Seconds(),
+		ScreenSeconds: stat.ScreenWorkDuration.Seconds(),
+		OtherSeconds:  stat.OtherWorkDuration.Seconds(),
+		BreakSeconds:  stat.BreakDuration.Seconds(),
+	}
+}
+
+func newJSONHours(stats db.HourlyStats) jsonHours {
+	hours := jsonHours{Sessions: stats.Sessions}
+	if stats.Sessions > 0 {
+		hours.AverageStart = components.FormatTimeOfDay(stats.AverageStart)
+	}
+
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		day := jsonHourlyWeek{
+			Weekday:     strings.ToLower(weekday.String()),
+			Sessions:    stats.WeekdaySessions[weekday],
+			WorkSeconds: make([]float64, 0, len(stats.Work[weekday])),
+		}
+
+		if day.Sessions > 0 {
+			day.AverageStart = components.FormatTimeOfDay(stats.WeekdayAverageStarts[weekday])
+		}
+
+		for _, duration := range stats.Work[weekday] {
+			day.WorkSeconds = append(day.WorkSeconds, duration.Seconds())
+		}
+
+		hours.Weekdays = append(hours.Weekdays, day)
+	}
+
+	return hours
+}
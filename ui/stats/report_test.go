@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bahaaio/pomo/db"
+)
+
+func testReport() Report {
+	return Report{
+		Range:  NewRange(DayRange, date(2026, 2, 8), utcCalendar),
+		Totals: db.AllTimeStats{TotalSessions: 3, TotalWorkDuration: 95 * time.Minute, TotalBreakDuration: 10 * time.Minute},
+		Days: []db.DailyStat{{
+			Date:               "2026-02-08",
+			WorkDuration:       95 * time.Minute,
+			ScreenWorkDuration: 68 * time.Minute,
+			OtherWorkDuration:  27 * time.Minute,
+			BreakDuration:      10 * time.Minute,
+		}},
+		Today:  db.DailyStat{Date: "2026-02-08", WorkDuration: 95 * time.Minute},
+		Streak: db.StreakStats{Current: 3, Best: 12, CurrentWeeks: 1, BestWeeks: 4},
+	}
+}
+
+func TestReportWritePlain(t *testing.T) {
+	var out bytes.Buffer
+	if err := testReport().WritePlain(&out); err != nil {
+		t.Fatalf("WritePlain() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"range:     Sun, Feb 8 2026 (day)",
+		"total:     3 sessions · work 1h35m · break 10m",
+		"streak:    3d · best 12d · weekly 1w · best 4w",
+		"2026-02-08  1h35m  1h8m    27m    10m",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("WritePlain() output is missing %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := testReport().WriteJSON(&out); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var got jsonReport
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid JSON: %v", err)
+	}
+
+	if got.Range.From != "2026-02-08" || got.Range.To != "2026-02-08" || got.Range.Kind != DayRange {
+		t.Fatalf("range = %+v, want the day 2026-02-08", got.Range)
+	}
+
+	if got.Totals.Sessions != 3 || got.Totals.WorkSeconds != 5700 {
+		t.Fatalf("totals = %+v, want 3 sessions and 5700 work seconds", got.Totals)
+	}
+
+	if len(got.Days) != 1 || got.Days[0].ScreenSeconds != 4080 || got.Days[0].OtherSeconds != 1620 {
+		t.Fatalf("days = %+v, want one day with 4080 screen and 1620 other seconds", got.Days)
+	}
+
+	if got.Streak.Current != 3 || got.Streak.BestWeeks != 4 {
+		t.Fatalf("streak = %+v, want current 3 and best weeks 4", got.Streak)
+	}
+}
@@ -4,25 +4,37 @@ package stats
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/Bahaaio/pomo/config"
 	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
 	"github.com/Bahaaio/pomo/ui/colors"
 	"github.com/Bahaaio/pomo/ui/stats/components"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
 	barChartHeight     = 12
+	heatMapGap         = 3
 	durationRatioWidth = 30
+	defaultProfileName = "default"
 )
 
-var errStyle = lipgloss.NewStyle().
-	Foreground(colors.ErrorMessageFg).
-	AlignHorizontal(lipgloss.Center)
+var (
+	errStyle = lipgloss.NewStyle().
+			Foreground(colors.ErrorMessageFg).
+			AlignHorizontal(lipgloss.Center)
+
+	rangeStyle      = lipgloss.NewStyle().Bold(true)
+	rangeHintStyle  = lipgloss.NewStyle().Foreground(colors.DimGray)
+	rangeErrorStyle = lipgloss.NewStyle().Foreground(colors.ErrorMessageFg)
+)
 
 type Model struct {
 	// components
@@ -30,37 +42,75 @@ type Model struct {
 	barChart      components.BarChart
 	heatMap       components.HeatMap
 	streak        components.Streak
+	punchCard     components.PunchCard
+	rangeInput    textinput.Model
 
 	// error message
 	err error
 
 	// stats
-	allTimeStats db.AllTimeStats
-	weeklyStats  []db.DailyStat
+	repo         *db.SessionRepo
+	calendar     db.Calendar
+	statsRange   Range
+	report       Report
 	monthlyStats []db.DailyStat
-	streakStats  db.StreakStats
 
 	// state
+	showHours     bool
+	weekNumbers   bool
+	heatMapYears  int       // years the heat map is scrolled back
+	heatMapShown  time.Time // last day of the heat map stats shown
+	editingRange  bool
+	rangeInputErr error
 	width, height int
 	help          help.Model
 	quitting      bool
 }
 
-func New() Model {
+// New returns the stats view, starting on statsRange.
+func New(statsRange Range, settings config.Stats) Model {
+	rangeInput := textinput.New()
+	rangeInput.Prompt = i18n.T("range: ")
+	rangeInput.Placeholder = "2026-01-01..2026-01-31, 30d, 2026-01"
+
+	heatMap := components.HeatMap{
+		Calendar:    statsRange.calendar,
+		Months:      settings.HeatMap.Months,
+		Quantiles:   settings.HeatMap.Thresholds == config.HeatMapQuantile,
+		WeekNumbers: settings.WeekNumbers,
+	}
+
 	return Model{
 		durationRatio: components.NewDurationRatio(durationRatioWidth),
 		barChart:      components.NewBarChart(barChartHeight),
-		heatMap:       components.NewHeatMap(),
+		heatMap:       heatMap,
 		streak:        components.NewStreak(),
+		punchCard:     components.NewPunchCard(),
+		rangeInput:    rangeInput,
+		calendar:      statsRange.calendar,
+		statsRange:    statsRange,
+		heatMapShown:  statsRange.calendar.Today(),
+		weekNumbers:   settings.WeekNumbers,
 		help:          help.New(),
 	}
 }
 
 type statsMsg struct {
-	allTimeStats db.AllTimeStats
-	weeklyStats  []db.DailyStat
+	repo         *db.SessionRepo
+	report       Report
 	monthlyStats []db.DailyStat
-	streakStats  db.StreakStats
+	heatMapEnd   time.Time
+}
+
+type rangeStatsMsg struct {
+	statsRange  Range
+	rangeStats  db.RangeStats
+	hourlyStats db.HourlyStats
+}
+
+type heatMapStatsMsg struct {
+	end   time.Time
+	stats []db.DailyStat
 }
 
 type errMsg struct {
@@ -69,44 +119,66 @@ type errMsg struct {
 
 // fetchStats retrieves statistics from the database and returns them as a statsMsg.
 // If an error occurs, it returns an errMsg instead.
-func fetchStats() tea.Msg {
-	database, err := db.Connect()
-	if err != nil {
-		return errMsg{err: errors.New("failed to connect to the database")}
-	}
+func fetchStats(statsRange Range, heatMapMonths int) tea.Cmd {
+	return func() tea.Msg {
+		database, err := db.Connect()
+		if err != nil {
+			return errMsg{err: errors.New("failed to connect to the database")}
+		}
 
-	repo := db.NewSessionRepo(database)
+		repo := db.NewSessionRepo(database)
 
-	stats, err := repo.GetAllTimeStats()
-	if err != nil {
-		return errMsg{err: errors.New("failed to fetch all-time stats")}
-	}
+		report, err := FetchReport(repo, statsRange)
+		if err != nil {
+			return errMsg{err: err}
+		}
 
-	weeklyStats, err := repo.GetWeeklyStats()
-	if err != nil {
-		return errMsg{err: errors.New("failed to fetch weekly stats")}
-	}
+		today := repo.Calendar().Today()
+		monthlyStats, err := repo.GetMonthsStats(today, heatMapMonths)
+		if err != nil {
+			return errMsg{err: errors.New("failed to fetch heatmap stats")}
+		}
 
-	monthlyStats, err := repo.GetLastMonthsStats(components.NumberOfMonths)
-	if err != nil {
-		return errMsg{err: errors.New("failed to fetch heatmap stats")}
+		return statsMsg{
+			repo:         repo,
+			report:       report,
+			monthlyStats: monthlyStats,
+			heatMapEnd:   today,
+		}
 	}
+}
+
+// fetchHeatMapStats retrieves the statistics of the heat map months up to end.
+func fetchHeatMapStats(repo *db.SessionRepo, end time.Time, months int) tea.Cmd {
+	return func() tea.Msg {
+		stats, err := repo.GetMonthsStats(end, months)
+		if err != nil {
+			return errMsg{err: errors.New("failed to fetch heatmap stats")}
+		}
 
-	streakStats, err := repo.GetStreakStats()
-	if err != nil {
-		return errMsg{err: errors.New("failed to fetch streak stats")}
+		return heatMapStatsMsg{end: end, stats: stats}
 	}
+}
+
+// fetchRangeStats retrieves the statistics of another range after the initial fetch.
+func fetchRangeStats(repo *db.SessionRepo, statsRange Range) tea.Cmd {
+	return func() tea.Msg {
+		rangeStats, err := repo.GetRangeStats(statsRange.From, statsRange.To)
+		if err != nil {
+			return errMsg{err: errors.New("failed to fetch range stats")}
+		}
 
-	return statsMsg{
-		allTimeStats: stats,
-		weeklyStats:  weeklyStats,
-		monthlyStats: monthlyStats,
-		streakStats:  streakStats,
+		hourlyStats, err := repo.GetHourlyStats(statsRange.From, statsRange.To)
+		if err != nil {
+			return errMsg{err: errors.New("failed to fetch hourly stats")}
+		}
+
+		return rangeStatsMsg{statsRange: statsRange, rangeStats: rangeStats, hourlyStats: hourlyStats}
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	return fetchStats
+	return fetchStats(m.statsRange, m.heatMap.MonthCount())
 }
 
 func (m Model) View() string {
@@ -118,20 +190,43 @@ func (m Model) View() string {
 		return m.buildErrorMessage()
 	}
 
-	title := "Pomodoro statistics"
+	title := i18n.T("Pomodoro statistics")
 
 	durationRatio := m.durationRatio.View(
-		m.allTimeStats.TotalWorkDuration,
-		m.allTimeStats.TotalBreakDuration,
+		m.report.Totals.TotalWorkDuration,
+		m.report.Totals.TotalBreakDuration,
 	)
 
-	streak := m.streak.View(m.streakStats)
-	todayWork := buildTodayWorkLine(m.weeklyStats, time.Now())
+	totals := buildTotalsLine(m.report.Totals)
+	if outcomes := buildOutcomesLine(m.report.Outcomes); outcomes != "" {
+		totals += "\n" + outcomes
+	}
+	if interruptions := buildInterruptionsLine(m.report.Interruptions); interruptions != "" {
+		totals += "\n" + interruptions
+	}
 
-	chart := m.barChart.View(m.weeklyStats)
-	hMap := m.heatMap.View(m.monthlyStats)
+	streak := m.streak.View(m.report.Streak)
+	todayWork := buildTodayWorkLine([]db.DailyStat{m.report.Today}, m.calendar.Today())
+	if profiles := buildProfilesLine(m.report.Profiles); profiles != "" {
+		todayWork += "\n" + profiles
+	}
 
-	charts := lipgloss.JoinHorizontal(lipgloss.Bottom, chart, "   ", hMap)
+	var charts string
+	if m.showHours {
+		charts = m.punchCard.View(m.report.Hours)
+	} else {
+		chart := m.barChart.View(m.statsRange.groupStats(m.report.Days, m.weekNumbers))
+		// the heat map shows fewer months if it doesn't fit next to the chart
+		heatMapWidth := 0
+		if m.width > 0 {
+			heatMapWidth = max(m.width-lipgloss.Width(chart)-heatMapGap, 1)
+		}
+
+		// the shown months change once their stats arrive
+		hMap := m.heatMap.View(m.monthlyStats, m.heatMapShown, heatMapWidth)
+
+		charts = lipgloss.JoinHorizontal(lipgloss.Bottom, chart, strings.Repeat(" ", heatMapGap), hMap)
+	}
 
 	return lipgloss.Place(
 		m.width, m.height,
@@ -139,8 +234,11 @@ func (m Model) View() string {
 		lipgloss.JoinVertical(
 			lipgloss.Center,
 			title,
-			"\n\n",
+			"",
+			m.buildRangeLine(),
+			"\n",
 			durationRatio,
+			totals,
 			"",
 			todayWork,
 			"",
@@ -148,7 +246,7 @@ func (m Model) View() string {
 			"\n",
 			charts,
 			"",
-			m.help.View(Keys),
+			m.buildHelp(),
 		),
 	)
 }
@@ -156,16 +254,49 @@ func (m Model) View() string {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case statsMsg:
-		m.allTimeStats = msg.allTimeStats
-		m.weeklyStats = msg.weeklyStats
+		m.repo = msg.repo
+		m.report = msg.report
 		m.monthlyStats = msg.monthlyStats
-		m.streakStats = msg.streakStats
+		m.heatMapShown = msg.heatMapEnd
+
+		var cmds []tea.Cmd
+
+		// the range was changed before the database connected
+		if msg.report.Range.String() != m.statsRange.String() {
+			cmds = append(cmds, fetchRangeStats(m.repo, m.statsRange))
+		}
+
+		// the heat map was scrolled before the database connected
+		if m.heatMapYears > 0 {
+			cmds = append(cmds, fetchHeatMapStats(m.repo, m.heatMapEnd(), m.heatMap.MonthCount()))
+		}
+		return m, tea.Batch(cmds...)
+	case heatMapStatsMsg:
+		// ignore stats of a year that was scrolled away from
+		if msg.end.Equal(m.heatMapEnd()) {
+			m.monthlyStats = msg.stats
+			m.heatMapShown = msg.end
+		}
+		return m, nil
+	case rangeStatsMsg:
+		// ignore stats of a range that was navigated away from
+		if msg.statsRange.String() == m.statsRange.String() {
+			m.report.Range = msg.statsRange
+			m.report.Totals = msg.rangeStats.Totals
+			m.report.Days = msg.rangeStats.Days
+			m.report.Outcomes = msg.rangeStats.Outcomes
+			m.report.Interruptions = msg.rangeStats.Interruptions
+			m.report.Hours = msg.hourlyStats
+		}
 		return m, nil
 	case errMsg:
 		m.err = msg.err
 		return m, nil
 	case tea.KeyMsg:
-		return m, handleKeys(msg)
+		if m.editingRange {
+			return m.handleRangeInput(msg)
+		}
+		return m.handleKeys(msg)
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -176,10 +307,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) buildErrorMessage() string {
-	title := "An error occurred while fetching statistics."
+	title := i18n.T("An error occurred while fetching statistics.")
 	message := m.err.Error()
 
-	help := m.help.View(KeyMap{Keys.Quit})
+	help := m.help.View(i18n.KeyMap{KeyMap: KeyMap{Quit: Keys.Quit}})
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Center,
@@ -197,12 +328,172 @@ func (m *Model) buildErrorMessage() string {
 	)
 }
 
-func handleKeys(msg tea.KeyMsg) tea.Cmd {
+func (m Model) handleKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	today := m.calendar.Today()
+
 	switch {
 	case key.Matches(msg, Keys.Quit):
-		return tea.Quit
+		return m, tea.Quit
+	case key.Matches(msg, Keys.Previous):
+		return m.setRange(m.statsRange.Shift(-1))
+	case key.Matches(msg, Keys.Next):
+		// there are no stats in the future
+		if next := m.statsRange.Shift(1); !next.From.After(today) {
+			return m.setRange(next)
+		}
+	case key.Matches(msg, Keys.Current):
+		return m.setRange(NewRange(m.statsRange.Kind, today, m.calendar))
+	case key.Matches(msg, Keys.Day):
+		return m.setRange(NewRange(DayRange, today, m.calendar))
+	case key.Matches(msg, Keys.Week):
+		return m.setRange(NewRange(WeekRange, today, m.calendar))
+	case key.Matches(msg, Keys.Month):
+		return m.setRange(NewRange(MonthRange, today, m.calendar))
+	case key.Matches(msg, Keys.Year):
+		return m.setRange(NewRange(YearRange, today, m.calendar))
+	case key.Matches(msg, Keys.Hours):
+		m.showHours = !m.showHours
+	case key.Matches(msg, Keys.WeekNumbers):
+		m.weekNumbers = !m.weekNumbers
+		m.heatMap.WeekNumbers = m.weekNumbers
+	case key.Matches(msg, Keys.OlderYear):
+		return m.scrollHeatMap(1)
+	case key.Matches(msg, Keys.NewerYear):
+		if m.heatMapYears > 0 {
+			return m.scrollHeatMap(-1)
+		}
+	case key.Matches(msg, Keys.Custom):
+		m.editingRange = true
+		m.rangeInputErr = nil
+		m.rangeInput.SetValue("")
+		return m, m.rangeInput.Focus()
+	}
+
+	return m, nil
+}
+
+func (m Model) handleRangeInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m, tea.Quit
+	case key.Matches(msg, Keys.Cancel):
+		m.editingRange = false
+		m.rangeInput.Blur()
+		return m, nil
+	case key.Matches(msg, Keys.Confirm):
+		statsRange, err := ParseRange(m.rangeInput.Value(), m.calendar)
+		if err != nil {
+			m.rangeInputErr = err
+			return m, nil
+		}
+
+		m.editingRange = false
+		m.rangeInput.Blur()
+		return m.setRange(statsRange)
+	}
+
+	var cmd tea.Cmd
+	m.rangeInput, cmd = m.rangeInput.Update(msg)
+	return m, cmd
+}
+
+// scrolls the heat map back by years, forward if years is negative
+func (m Model) scrollHeatMap(years int) (tea.Model, tea.Cmd) {
+	m.heatMapYears += years
+
+	if m.repo == nil {
+		return m, nil
+	}
+
+	return m, fetchHeatMapStats(m.repo, m.heatMapEnd(), m.heatMap.MonthCount())
+}
+
+// returns the last day of the heat map scrolled to: today, or the last day of a past year
+func (m Model) heatMapEnd() time.Time {
+	today := m.calendar.Today()
+	if m.heatMapYears == 0 {
+		return today
 	}
-	return nil
+
+	return time.Date(today.Year()-m.heatMapYears, time.December, 31, 0, 0, 0, 0, today.Location())
+}
+
+// switches to statsRange and fetches its stats
+func (m Model) setRange(statsRange Range) (tea.Model, tea.Cmd) {
+	m.statsRange = statsRange
+
+	// fetched once the database connected
+	if m.repo == nil {
+		return m, nil
+	}
+
+	return m, fetchRangeStats(m.repo, statsRange)
+}
+
+func (m Model) buildRangeLine() string {
+	if m.editingRange {
+		line := m.rangeInput.View()
+		if m.rangeInputErr != nil {
+			line += "\n" + rangeErrorStyle.Render(m.rangeInputErr.Error())
+		}
+
+		return line
+	}
+
+	hint := i18n.T(string(m.statsRange.Kind))
+	if m.weekNumbers && m.statsRange.Kind == WeekRange {
+		hint += " " + m.statsRange.weekLabel(m.statsRange.From)
+	}
+
+	return rangeStyle.Render(m.statsRange.Title()) + rangeHintStyle.Render(" ("+hint+")")
+}
+
+func (m Model) buildHelp() string {
+	if m.editingRange {
+		return m.help.ShortHelpView(i18n.Bindings([]key.Binding{Keys.Confirm, Keys.Cancel}))
+	}
+
+	return m.help.View(i18n.KeyMap{KeyMap: Keys})
+}
+
+// builds a line with the totals of the range
+func buildTotalsLine(totals db.AllTimeStats) string {
+	return i18n.Tf(
+		"%d sessions · work %s · break %s",
+		totals.TotalSessions,
+		formatDurationCompact(totals.TotalWorkDuration),
+		formatDurationCompact(totals.TotalBreakDuration),
+	)
+}
+
+// builds a line with how the work sessions of the range ended,
+// returns an empty string if no outcomes were recorded
+func buildOutcomesLine(outcomes db.OutcomeStats) string {
+	if outcomes.Sessions == 0 {
+		return ""
+	}
+
+	return i18n.Tf(
+		"completed %.0f%% · overrun %s avg · %.1f skipped/quit per day",
+		outcomes.CompletionRate()*100,
+		formatDurationCompact(outcomes.AverageOverrun()),
+		outcomes.AbandonedPerDay(),
+	)
+}
+
+// builds a line with the interruptions logged during the sessions of the range,
+// returns an empty string if none were logged
+func buildInterruptionsLine(interruptions db.InterruptionStats) string {
+	if interruptions.Total() == 0 {
+		return ""
+	}
+
+	return i18n.Tf(
+		"%d logged interruptions · %d internal · %d external",
+		interruptions.Total(),
+		interruptions.Internal,
+		interruptions.External,
+	)
 }
 
 func buildTodayWorkLine(stats []db.DailyStat, now time.Time) string {
@@ -220,7 +511,7 @@ func buildTodayWorkLine(stats []db.DailyStat, now time.Time) string {
 		}
 	}
 
-	return fmt.Sprintf(
+	return i18n.Tf(
 		"today work total %s · screen %s · other %s",
 		formatDurationCompact(total),
 		formatDurationCompact(screen),
@@ -228,6 +519,26 @@ func buildTodayWorkLine(stats []db.DailyStat, now time.Time) string {
 	)
 }
 
+// builds a line with the work duration of each profile,
+// returns an empty string if no profiles were used
+func buildProfilesLine(stats []db.ProfileStats) string {
+	if len(stats) == 0 || (len(stats) == 1 && stats[0].Profile == "") {
+		return ""
+	}
+
+	parts := make([]string, 0, len(stats))
+	for _, stat := range stats {
+		name := stat.Profile
+		if name == "" {
+			name = i18n.T(defaultProfileName)
+		}
+
+		parts = append(parts, name+" "+formatDurationCompact(stat.TotalWorkDuration))
+	}
+
+	return i18n.T("profiles") + " " + strings.Join(parts, " · ")
+}
+
 func formatDurationCompact(d time.Duration) string {
 	if d <= 0 {
 		return "0m"
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Bahaaio/pomo/config"
 	"github.com/Bahaaio/pomo/db"
 )
 
@@ -42,3 +43,104 @@ func TestBuildTodayWorkLine_NoTodayData(t *testing.T) {
 		t.Fatalf("buildTodayWorkLine() = %q, want %q", got, want)
 	}
 }
+
+func TestBuildProfilesLine(t *testing.T) {
+	stats := []db.ProfileStats{
+		{Profile: "deep", AllTimeStats: db.AllTimeStats{TotalWorkDuration: 90 * time.Minute}},
+		{Profile: "", AllTimeStats: db.AllTimeStats{TotalWorkDuration: 25 * time.Minute}},
+	}
+
+	got := buildProfilesLine(stats)
+	want := "profiles deep 1h30m · default 25m"
+
+	if got != want {
+		t.Fatalf("buildProfilesLine() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildProfilesLine_NoProfiles(t *testing.T) {
+	stats := []db.ProfileStats{
+		{Profile: "", AllTimeStats: db.AllTimeStats{TotalWorkDuration: 25 * time.Minute}},
+	}
+
+	if got := buildProfilesLine(stats); got != "" {
+		t.Fatalf("buildProfilesLine() = %q, want empty string", got)
+	}
+}
+
+func TestBuildOutcomesLine(t *testing.T) {
+	outcomes := db.OutcomeStats{
+		Sessions:   5,
+		Completed:  3,
+		Extended:   1,
+		Skipped:    1,
+		Overrun:    4 * time.Minute,
+		ActiveDays: 2,
+	}
+
+	got := buildOutcomesLine(outcomes)
+	want := "completed 80% · overrun 1m avg · 0.5 skipped/quit per day"
+
+	if got != want {
+		t.Fatalf("buildOutcomesLine() = %q, want %q", got, want)
+	}
+
+	if got := buildOutcomesLine(db.OutcomeStats{}); got != "" {
+		t.Fatalf("buildOutcomesLine() = %q, want empty string", got)
+	}
+}
+
+func TestBuildInterruptionsLine(t *testing.T) {
+	got := buildInterruptionsLine(db.InterruptionStats{Internal: 2, External: 1})
+	want := "3 logged interruptions · 2 internal · 1 external"
+
+	if got != want {
+		t.Fatalf("buildInterruptionsLine() = %q, want %q", got, want)
+	}
+
+	if got := buildInterruptionsLine(db.InterruptionStats{}); got != "" {
+		t.Fatalf("buildInterruptionsLine() = %q, want empty string", got)
+	}
+}
+
+func TestUpdate_StatsMsgRefetchesChangedRange(t *testing.T) {
+	initial := NewRange(WeekRange, date(2026, 2, 11), utcCalendar)
+
+	testCases := []struct {
+		name       string
+		statsRange Range
+		wantFetch  bool
+	}{
+		{"same range", initial, false},
+		{"changed while connecting", initial.Shift(-1), true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(tt.statsRange, config.Stats{})
+
+			_, cmd := m.Update(statsMsg{repo: &db.SessionRepo{}, report: Report{Range: initial}})
+			if (cmd != nil) != tt.wantFetch {
+				t.Fatalf("Update() fetched = %v, want %v", cmd != nil, tt.wantFetch)
+			}
+		})
+	}
+}
+
+func TestScrollHeatMap_KeepsShownYearUntilStatsArrive(t *testing.T) {
+	m := New(NewRange(WeekRange, date(2026, 2, 11), utcCalendar), config.Stats{})
+	m.repo = &db.SessionRepo{}
+	today := m.heatMapShown
+
+	scrolled, _ := m.scrollHeatMap(1)
+	m = scrolled.(Model)
+	if !m.heatMapShown.Equal(today) {
+		t.Fatalf("shown end = %v before the stats arrived, want %v", m.heatMapShown, today)
+	}
+
+	end := m.heatMapEnd()
+	updated, _ := m.Update(heatMapStatsMsg{end: end})
+	if shown := updated.(Model).heatMapShown; !shown.Equal(end) {
+		t.Fatalf("shown end = %v after the stats arrived, want %v", shown, end)
+	}
+}
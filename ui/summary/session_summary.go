@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Bahaaio/pomo/config"
+	"github.com/Bahaaio/pomo/db"
+	"github.com/Bahaaio/pomo/i18n"
 	"github.com/Bahaaio/pomo/ui/colors"
+	"github.com/Bahaaio/pomo/ui/reflection"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -23,9 +27,22 @@ type SessionSummary struct {
 	totalBreakSessions int
 	totalBreakDuration time.Duration
 
+	notes []note
+
+	internalInterruptions int
+	externalInterruptions int
+
+	pausedDuration time.Duration
+
 	isDatabaseUnavailable bool
 }
 
+// note written after a work session
+type note struct {
+	text   string
+	rating int
+}
+
 // AddSession adds a session to the summary based on the task type and elapsed time.
 func (t *SessionSummary) AddSession(taskType config.TaskType, elapsed time.Duration) {
 	if taskType == config.WorkTask {
@@ -46,6 +63,25 @@ func (t *SessionSummary) AddDuration(taskType config.TaskType, duration time.Dur
 	}
 }
 
+// AddNote adds the note and rating written after a work session.
+func (t *SessionSummary) AddNote(text string, rating int) {
+	t.notes = append(t.notes, note{text: text, rating: rating})
+}
+
+// AddInterruption counts an interruption logged during a session.
+func (t *SessionSummary) AddInterruption(kind db.InterruptionKind) {
+	if kind == db.InternalInterruption {
+		t.internalInterruptions++
+	} else {
+		t.externalInterruptions++
+	}
+}
+
+// AddPausedDuration adds the length of a pause.
+func (t *SessionSummary) AddPausedDuration(duration time.Duration) {
+	t.pausedDuration += duration
+}
+
 // SetDatabaseUnavailable marks the database as unavailable.
 // prints a warning in the summary.
 func (t *SessionSummary) SetDatabaseUnavailable() {
@@ -58,36 +94,67 @@ func (t SessionSummary) Print() {
 		return
 	}
 
-	workIndicator := "sessions"
-	if t.totalWorkSessions == 1 {
-		workIndicator = "session"
-	}
+	workIndicator := i18n.Plural(t.totalWorkSessions, "session", "sessions")
+	breakIndicator := i18n.Plural(t.totalBreakSessions, "session", "sessions")
 
-	breakIndicator := "sessions"
-	if t.totalBreakSessions == 1 {
-		breakIndicator = "session"
-	}
+	fmt.Println(messageStyle.Render(i18n.T("Session Summary:")))
 
-	fmt.Println(messageStyle.Render("Session Summary:"))
+	// work and break are aligned with each other
+	workLabel, breakLabel := alignLabels(i18n.T("Work"), i18n.T("Break"))
 
 	if t.totalWorkDuration > 0 {
-		fmt.Printf(" Work : %v (%d %s)\n", t.totalWorkDuration, t.totalWorkSessions, workIndicator)
+		fmt.Printf(" %s: %v (%d %s)\n", workLabel, t.totalWorkDuration, t.totalWorkSessions, workIndicator)
 	}
 
 	if t.totalBreakDuration > 0 {
-		fmt.Printf(" Break: %v (%d %s)\n", t.totalBreakDuration, t.totalBreakSessions, breakIndicator)
+		fmt.Printf(" %s: %v (%d %s)\n", breakLabel, t.totalBreakDuration, t.totalBreakSessions, breakIndicator)
 	}
 
 	if t.totalBreakDuration > 0 && t.totalWorkDuration > 0 {
-		fmt.Println(" Total:", t.totalWorkDuration+t.totalBreakDuration)
+		fmt.Printf(" %s: %v\n", i18n.T("Total"), t.totalWorkDuration+t.totalBreakDuration)
+	}
+
+	if t.pausedDuration >= time.Second {
+		fmt.Printf(" %s: %v\n", i18n.T("Paused"), t.pausedDuration.Truncate(time.Second))
+	}
+
+	if t.internalInterruptions+t.externalInterruptions > 0 {
+		fmt.Printf(" %s: %s\n", i18n.T("Interruptions"),
+			i18n.Tf("%d internal, %d external", t.internalInterruptions, t.externalInterruptions))
 	}
 
 	if t.totalWorkDuration > 0 {
 		t.printProgressBar()
 	}
 
+	if len(t.notes) > 0 {
+		t.printNotes()
+	}
+
 	if t.isDatabaseUnavailable {
-		fmt.Println(errorStyle.Render("\n Not saved (database unavailable)"))
+		fmt.Println(errorStyle.Render("\n " + i18n.T("Not saved (database unavailable)")))
+	}
+}
+
+// pads the shorter of two labels to the length of the longer one
+func alignLabels(a, b string) (string, string) {
+	width := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+
+	return a + strings.Repeat(" ", width-utf8.RuneCountInString(a)),
+		b + strings.Repeat(" ", width-utf8.RuneCountInString(b))
+}
+
+// prints the notes of the work sessions with their ratings
+func (t SessionSummary) printNotes() {
+	fmt.Println("\n " + i18n.T("Notes:"))
+
+	for _, note := range t.notes {
+		line := note.text
+		if rating := reflection.FormatRating(note.rating); rating != "" {
+			line = strings.TrimSpace(line + " " + rating)
+		}
+
+		fmt.Println("  - " + line)
 	}
 }
 
@@ -105,5 +172,5 @@ func (t SessionSummary) printProgressBar() {
 		Render(strings.Repeat("█", filledWidth)) +
 		strings.Repeat("░", emptyWidth)
 
-	fmt.Printf("\n [%s] %.0f%% work\n", bar, workRatio*100)
+	fmt.Printf("\n [%s] %s\n", bar, i18n.Tf("%.0f%% work", workRatio*100))
 }